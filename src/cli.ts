@@ -0,0 +1,421 @@
+#!/usr/bin/env node
+/**
+ * Auto Docker CLI
+ * Thin wrapper over the library API (index.ts) - same pipeline as the extension
+ */
+
+import * as fs from 'fs';
+import * as path from 'path';
+import { DockerGenerationOrchestrator } from './dockerGenerationOrchestrator';
+import { detect, generateResult, toFileMap, EnhancedDetectionResult, Options, Project } from './index';
+import { KNOWN_STACKS, ResourceLimits, StackKey, validateResourceLimit } from './projectConfig';
+import { verify } from './verify';
+import { buildErrorReport, buildReport } from './report';
+import { setVerbosity } from './logger';
+
+interface CliOptions {
+    command: 'generate' | 'verify';
+    targetPath: string;
+    docker: boolean;
+    outputDir?: string;
+    dryRun: boolean;
+    json: boolean;
+    force: boolean;
+    incremental: boolean;
+    writeGenerated: boolean;
+    githubActions: boolean;
+    makefile: boolean;
+    k8s: boolean;
+    dev: boolean;
+    databases: boolean;
+    limits?: ResourceLimits;
+    swarm: boolean;
+    strict: boolean;
+    registry?: string;
+    imagePrefix?: string;
+    recursive: boolean;
+    stack?: string;
+    concurrency?: number;
+    root: boolean;
+    minimal: boolean;
+    platforms?: string[];
+    templatesDir?: string;
+    buildTags?: string[];
+    verbose: number;
+    help: boolean;
+}
+
+/**
+ * --multi-arch targets when --platforms is not given
+ */
+const DEFAULT_PLATFORMS = ['linux/amd64', 'linux/arm64'];
+
+const USAGE = `Usage: auto-docker [path] [options]
+       auto-docker verify [path] [--docker] [options]
+
+Detects the project stack and generates Dockerfiles, docker-compose.yml,
+nginx.conf and .dockerignore files.
+
+verify generates into a temporary copy of the project (the project is not
+touched) and, with --docker, runs docker build for every generated Dockerfile.
+It exits non-zero if generation or any build fails.
+
+Options:
+  --dry-run    Print every generated file path and its contents; write nothing
+  --json       Print a JSON report (schemaVersion, services, files, warnings,
+               errors) on stdout instead of text; with --dry-run the files'
+               contents are included and nothing is written
+  --output-dir <dir>
+               Write generated files under <dir> (same relative layout)
+               instead of into the project
+  --force      Overwrite existing Dockerfiles (kept by default) and files
+               that already exist in --output-dir
+  --write-generated
+               Write Dockerfile.generated next to an existing Dockerfile
+               so the two can be diffed
+  --incremental
+               Only regenerate services whose inputs (manifests, entry
+               source, detection, options) changed since the last run, and
+               never rewrite a file whose content is unchanged; state is
+               kept in .autodocker.cache
+  --recursive  Walk the tree and generate a service for every project root
+               (skips node_modules and vendor)
+  --github-actions
+               Also write .github/workflows/docker.yml that builds and pushes
+               every service image on tag (skipped if it already exists)
+  --registry <host>
+               Registry for the workflow (default: ghcr.io); implies --github-actions
+  --image-prefix <name>
+               Image namespace, e.g. myorg/myapp (default: the GitHub
+               repository); implies --github-actions
+  --makefile   Also write a Makefile with docker-build/docker-run/docker-clean
+               targets per service (skipped if a Makefile exists)
+  --k8s        Also write k8s/<service>.yaml with a Deployment and a Service
+               per built service (same names, ports and env as compose;
+               probes on a detected health route)
+  --dev        Also write docker-compose.override.yml that runs detected
+               hot-reload tooling (air, nodemon, vite, ...) with the source
+               mounted; \`docker compose up\` picks it up automatically
+  --no-databases
+               Do not add detected databases (postgres, mysql, mongodb, redis)
+               to docker-compose.yml
+  --limits     Add deploy.resources.limits to every compose service
+               (default 0.5 CPU / 512M; per-service values in .autodocker.yaml)
+  --cpus <n>   CPU limit per service, e.g. 1.5; implies --limits
+  --memory <size>
+               Memory limit per service, e.g. 1G; implies --limits
+  --swarm      Write docker-compose.yml for docker stack deploy
+               (deploy.restart_policy, overlay network, no container_name)
+  --strict     Treat Dockerfile lint warnings (unpinned images, dependency
+               installs after COPY . ., ...) as errors and write nothing
+  --stack <name>
+               Skip detection and treat the target directory as one stack:
+               ${KNOWN_STACKS.join(', ')}
+  --concurrency <n>
+               Scan at most <n> services at once (default: one per CPU);
+               a failing service is reported and the rest are still generated
+  --root       Keep root in the final stage instead of an unprivileged USER
+  --minimal    Use gcr.io/distroless runtime images (Go, Java and Python
+               backends only; fails for any other detected backend)
+  --multi-arch Make Dockerfiles and the workflow buildx multi-arch ready for
+               linux/amd64,linux/arm64 (builders on $BUILDPLATFORM, Go
+               cross-compiled from TARGETOS/TARGETARCH)
+  --platforms <list>
+               Comma-separated targets for --multi-arch, e.g.
+               linux/amd64,linux/arm64,linux/arm/v7; implies --multi-arch
+  --templates-dir <dir>
+               Render Dockerfiles from <stack>.Dockerfile.tmpl files in <dir>
+               (e.g. go.Dockerfile.tmpl) instead of the built-in templates;
+               stacks without a file keep the built-in one
+  --build-tags <list>
+               Comma-separated Go build tags for go build -tags, replacing
+               the tags detected from //go:build constraints; required when
+               those are ambiguous (--build-tags= builds without tags)
+  -v, --verbose
+               Also print detection decisions: which detector won in each
+               directory and what else matched, monorepo/deep-scan choices,
+               every detected route and the one chosen for each HEALTHCHECK
+  -vv          Also trace every detector run: the files it looked for,
+               which exist, and whether it matched
+  --docker     (verify) Run docker build for each generated Dockerfile;
+               without it verify stops after generation and linting
+  -h, --help   Show this help
+`;
+
+function parseArgs(argv: string[]): CliOptions {
+    const options: CliOptions = { command: 'generate', targetPath: '.', docker: false, dryRun: false, json: false, force: false, incremental: false, writeGenerated: false, githubActions: false, makefile: false, k8s: false, dev: false, databases: true, swarm: false, strict: false, recursive: false, root: false, minimal: false, verbose: 0, help: false };
+
+    // Value flags accept both `--flag value` and `--flag=value`
+    const takeValue = (arg: string, index: number, what: string): [string, number] => {
+        const flag = arg.split('=')[0];
+        const value = arg.includes('=') ? arg.slice(arg.indexOf('=') + 1) : argv[index + 1];
+        if (!value || value.startsWith('-')) {
+            throw new Error(`${flag} requires ${what}`);
+        }
+        return [value, arg.includes('=') ? index : index + 1];
+    };
+
+    if (argv[0] === 'verify') {
+        options.command = 'verify';
+        argv = argv.slice(1);
+    }
+
+    for (let i = 0; i < argv.length; i++) {
+        const arg = argv[i];
+        const flag = arg.split('=')[0];
+        if (arg === '--json') {
+            options.json = true;
+        } else if (arg === '--dry-run') {
+            options.dryRun = true;
+        } else if (flag === '--output-dir') {
+            [options.outputDir, i] = takeValue(arg, i, 'a directory');
+        } else if (flag === '--stack') {
+            [options.stack, i] = takeValue(arg, i, 'a stack name');
+            if (!(KNOWN_STACKS as readonly string[]).includes(options.stack)) {
+                throw new Error(`Unknown stack "${options.stack}" for --stack (expected one of: ${KNOWN_STACKS.join(', ')})`);
+            }
+        } else if (flag === '--concurrency') {
+            let value: string;
+            [value, i] = takeValue(arg, i, 'a worker count');
+            options.concurrency = Number(value);
+            if (!Number.isInteger(options.concurrency) || options.concurrency < 1) {
+                throw new Error(`--concurrency must be a positive integer, got "${value}"`);
+            }
+        } else if (arg === '--docker') {
+            options.docker = true;
+        } else if (arg === '--strict') {
+            options.strict = true;
+        } else if (arg === '--no-databases') {
+            options.databases = false;
+        } else if (arg === '--limits') {
+            options.limits = options.limits || {};
+        } else if (flag === '--cpus' || flag === '--memory') {
+            const key = flag === '--cpus' ? 'cpus' : 'memory';
+            let value: string;
+            [value, i] = takeValue(arg, i, key === 'cpus' ? 'a CPU count' : 'a memory size');
+            const problem = validateResourceLimit(key, value);
+            if (problem) {
+                throw new Error(`${flag}: ${problem}`);
+            }
+            options.limits = { ...options.limits, [key]: value };
+        } else if (arg === '--swarm') {
+            options.swarm = true;
+        } else if (arg === '--dev') {
+            options.dev = true;
+        } else if (arg === '--makefile') {
+            options.makefile = true;
+        } else if (arg === '--k8s') {
+            options.k8s = true;
+        } else if (arg === '--github-actions') {
+            options.githubActions = true;
+        } else if (flag === '--registry') {
+            [options.registry, i] = takeValue(arg, i, 'a registry host');
+            options.githubActions = true;
+        } else if (flag === '--image-prefix') {
+            [options.imagePrefix, i] = takeValue(arg, i, 'an image name prefix');
+            options.githubActions = true;
+        } else if (arg === '--force') {
+            options.force = true;
+        } else if (arg === '--incremental') {
+            options.incremental = true;
+        } else if (arg === '--write-generated') {
+            options.writeGenerated = true;
+        } else if (arg === '--recursive') {
+            options.recursive = true;
+        } else if (arg === '--root') {
+            options.root = true;
+        } else if (arg === '--minimal') {
+            options.minimal = true;
+        } else if (arg === '--multi-arch') {
+            options.platforms = options.platforms || [...DEFAULT_PLATFORMS];
+        } else if (flag === '--platforms') {
+            let value: string;
+            [value, i] = takeValue(arg, i, 'a platform list');
+            options.platforms = value.split(',').map(p => p.trim()).filter(Boolean);
+            const invalid = options.platforms.filter(p => !/^[a-z0-9]+\/[a-z0-9]+(\/v\d+)?$/.test(p));
+            if (invalid.length > 0 || options.platforms.length === 0) {
+                throw new Error(`--platforms expects os/arch[/variant] entries, got "${value}"`);
+            }
+        } else if (flag === '--templates-dir') {
+            [options.templatesDir, i] = takeValue(arg, i, 'a directory');
+        } else if (arg === '--build-tags=') {
+            options.buildTags = [];
+        } else if (flag === '--build-tags') {
+            let value: string;
+            [value, i] = takeValue(arg, i, 'a tag list');
+            options.buildTags = value.split(/[\s,]+/).filter(Boolean);
+            const invalid = options.buildTags.filter(tag => !/^[\w.]+$/.test(tag));
+            if (invalid.length > 0) {
+                throw new Error(`--build-tags expects comma-separated Go build tags, got "${value}"`);
+            }
+        } else if (arg === '-v' || arg === '--verbose') {
+            options.verbose++;
+        } else if (arg === '-vv') {
+            options.verbose += 2;
+        } else if (arg === '-h' || arg === '--help') {
+            options.help = true;
+        } else if (arg.startsWith('-')) {
+            throw new Error(`Unknown option: ${arg}`);
+        } else {
+            options.targetPath = arg;
+        }
+    }
+
+    if (options.json && options.command === 'verify') {
+        throw new Error('--json is not supported by the verify command');
+    }
+
+    if (options.docker && options.command !== 'verify') {
+        throw new Error('--docker is only used by the verify command');
+    }
+
+    if (options.incremental && options.outputDir) {
+        throw new Error('--incremental compares against the files in the project and cannot be combined with --output-dir');
+    }
+
+    if (options.stack && options.recursive) {
+        throw new Error('--stack applies to a single directory and cannot be combined with --recursive');
+    }
+
+    return options;
+}
+
+/**
+ * Verbose detection details on stderr
+ */
+function printDetectionDetails(detection: EnhancedDetectionResult): void {
+    const backends = detection.monorepo?.backends || (detection.backend?.exists ? [detection.backend] : []);
+    for (const backend of backends) {
+        if (!backend.routes) continue;
+        console.error(`Routes in ${backend.path} (${backend.framework}):`);
+        for (const route of backend.routes) {
+            console.error(`  ${route}`);
+        }
+        console.error(`HEALTHCHECK path: ${backend.healthCheckPath}`);
+    }
+}
+
+/**
+ * verify: build every generated Dockerfile in a temp copy and report pass/fail per file
+ */
+async function runVerify(project: Project, generation: Options, docker: boolean): Promise<number> {
+    const result = await verify(project, { docker, generation, log: line => console.error(line) });
+
+    for (const e of result.generation.errors) {
+        console.error(`❌ ${e.path}: ${e.message}`);
+    }
+    for (const build of result.builds) {
+        if (build.status === 'passed') {
+            console.error(`✅ ${build.dockerfile} builds`);
+        } else if (build.status === 'skipped') {
+            console.error(`⏭️  ${build.dockerfile} generated and linted (pass --docker to build it)`);
+        } else {
+            console.error(`❌ ${build.dockerfile} failed to build:\n${build.output?.replace(/^/gm, '   ')}`);
+        }
+    }
+
+    console.error(result.passed ? '\nVerify passed' : '\nVerify failed');
+    return result.passed ? 0 : 1;
+}
+
+async function main(): Promise<number> {
+    const options = parseArgs(process.argv.slice(2));
+    if (options.help) {
+        process.stdout.write(USAGE);
+        return 0;
+    }
+
+    // Diagnostics go to stderr so stdout only carries generated content
+    console.log = console.error;
+    setVerbosity(options.verbose);
+
+    const project = await detect(options.targetPath, {
+        recursive: options.recursive,
+        stack: options.stack as StackKey | undefined,
+        concurrency: options.concurrency
+    });
+    if (options.verbose > 0) {
+        printDetectionDetails(project.detection);
+    }
+    // A separate output dir never touches the project's own Dockerfiles
+    const existingDockerfile = options.force || options.outputDir ? 'overwrite' : options.writeGenerated ? 'generated' : 'skip';
+    const generation: Options = {
+        runAsRoot: options.root,
+        distroless: options.minimal,
+        platforms: options.platforms,
+        incremental: options.incremental,
+        existingDockerfile,
+        makefile: options.makefile,
+        kubernetes: options.k8s,
+        devOverride: options.dev,
+        skipDatabases: !options.databases,
+        resourceLimits: options.limits ? { default: options.limits } : undefined,
+        swarm: options.swarm,
+        templatesDir: options.templatesDir && path.resolve(options.templatesDir),
+        goBuildTags: options.buildTags,
+        strict: options.strict,
+        githubWorkflow: options.githubActions ? { registry: options.registry, imagePrefix: options.imagePrefix } : undefined
+    };
+
+    if (options.command === 'verify') {
+        return runVerify(project, generation, options.docker);
+    }
+
+    const result = await generateResult(project, generation);
+    // Sorted so output never depends on which service finished first
+    const outputs = Object.entries(toFileMap(result))
+        .map(([filePath, content]) => ({ path: filePath, content }))
+        .sort((a, b) => a.path.localeCompare(b.path));
+    // Failed services were reported; still exit non-zero so scripts notice
+    const exitCode = result.errors.length > 0 ? 1 : 0;
+
+    if (options.dryRun && options.json) {
+        process.stdout.write(JSON.stringify(buildReport(project.root, result, outputs, { dryRun: true }), null, 2) + '\n');
+        return exitCode;
+    }
+
+    if (options.dryRun) {
+        if (result.detectionResult?.monorepo) {
+            console.error(DockerGenerationOrchestrator.formatServiceTable(result.detectionResult));
+        }
+        for (const f of outputs) {
+            process.stdout.write(`==> ${f.path} <==\n${f.content}`);
+            if (!f.content.endsWith('\n')) process.stdout.write('\n');
+            process.stdout.write('\n');
+        }
+        for (const e of result.errors) {
+            console.error(`❌ ${e.path}: ${e.message}`);
+        }
+        return exitCode;
+    }
+
+    // RULE: In-place writes keep the existing behavior; a separate output dir is never clobbered without --force
+    const outputRoot = options.outputDir ? path.resolve(options.outputDir) : project.root;
+    if (options.outputDir && !options.force) {
+        const existing = outputs.filter(f => fs.existsSync(path.join(outputRoot, f.path))).map(f => f.path);
+        if (existing.length > 0) {
+            throw new Error(`${options.outputDir} already contains ${existing.join(', ')} (use --force to overwrite)`);
+        }
+    }
+
+    DockerGenerationOrchestrator.writeOutputFiles(outputRoot, outputs, { appendLine: line => console.error(line) });
+    if (options.json) {
+        process.stdout.write(JSON.stringify(buildReport(project.root, result, outputs, { dryRun: false, outputRoot }), null, 2) + '\n');
+    } else {
+        console.error(DockerGenerationOrchestrator.generateSummary(result));
+    }
+    return exitCode;
+}
+
+main().then(code => {
+    process.exitCode = code;
+}).catch(error => {
+    const message = error instanceof Error ? error.message : String(error);
+    console.error(`❌ Error: ${message}`);
+    // --json consumers get a report even when the run stops early
+    const argv = process.argv.slice(2);
+    if (argv.includes('--json')) {
+        process.stdout.write(JSON.stringify(buildErrorReport(message, argv.includes('--dry-run')), null, 2) + '\n');
+    }
+    process.exitCode = 1;
+});
@@ -0,0 +1,51 @@
+/**
+ * Bounded Concurrency Helpers
+ *
+ * Used to scan and detect many services at once in large monorepos.
+ * RULE: Results always come back in input order, so output stays deterministic.
+ */
+
+import * as os from 'os';
+
+export interface PoolResult<T, R> {
+    item: T;
+    value?: R;
+    error?: Error;
+}
+
+/**
+ * Default worker count: one per available CPU
+ */
+export function defaultConcurrency(): number {
+    const available = typeof os.availableParallelism === 'function' ? os.availableParallelism() : os.cpus().length;
+    return Math.max(1, available);
+}
+
+/**
+ * Run fn over items with at most `limit` calls in flight
+ * A failing item records its error instead of rejecting the whole batch
+ */
+export async function mapWithConcurrency<T, R>(
+    items: T[],
+    limit: number,
+    fn: (item: T) => Promise<R>
+): Promise<Array<PoolResult<T, R>>> {
+    const results: Array<PoolResult<T, R>> = new Array(items.length);
+    let next = 0;
+
+    const worker = async () => {
+        while (next < items.length) {
+            const index = next++;
+            const item = items[index];
+            try {
+                results[index] = { item, value: await fn(item) };
+            } catch (error) {
+                results[index] = { item, error: error instanceof Error ? error : new Error(String(error)) };
+            }
+        }
+    };
+
+    const workers = Math.max(1, Math.min(limit, items.length));
+    await Promise.all(Array.from({ length: workers }, worker));
+    return results;
+}
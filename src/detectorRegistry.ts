@@ -0,0 +1,69 @@
+/**
+ * Detector Registry
+ *
+ * Every stack detector - built-in or registered by a library user - runs through this list.
+ * RULE: Highest priority first, ties in registration order; the first match per role wins.
+ *
+ * Built-ins register at priority 0 in this order:
+ *   frontend, node, python, go, java, php, ruby, rust, dotnet, elixir, haskell
+ * Register with priority > 0 to run before them, or under a built-in's name to replace it.
+ */
+
+import type { DetectedBackend, DetectedFrontend } from './enhancedDetectionEngine';
+
+export type DetectedService = DetectedFrontend | DetectedBackend;
+
+export interface DetectorContext {
+    root: string;          // Absolute project root being scanned
+    relativePath: string;  // Service directory relative to root ("." for the root itself)
+}
+
+export interface Detector {
+    name: string;                  // Unique; registering an existing name replaces that detector
+    role: 'frontend' | 'backend';  // Slot a match fills - a directory holds at most one of each
+    priority?: number;             // Higher runs first (default 0)
+    markers?: string[];            // Extra file names that make a directory a project root in recursive scans
+    /** Inspect dir and return the service found there, or undefined when this stack is absent */
+    detect(dir: string, context: DetectorContext): DetectedService | undefined | Promise<DetectedService | undefined>;
+    /** Files for a service this detector found, keyed by path relative to the service directory; omit to use the built-in templates */
+    generate?(service: DetectedService): Record<string, string>;
+}
+
+const registry: Array<{ detector: Detector; order: number }> = [];
+let registrations = 0;
+
+/**
+ * Add a detector; one with the same name is replaced and keeps its place in the order
+ */
+export function registerDetector(detector: Detector): void {
+    const existing = registry.findIndex(entry => entry.detector.name === detector.name);
+    if (existing >= 0) {
+        registry[existing] = { detector, order: registry[existing].order };
+    } else {
+        registry.push({ detector, order: registrations++ });
+    }
+}
+
+/**
+ * Remove a detector by name; returns false when none was registered
+ */
+export function unregisterDetector(name: string): boolean {
+    const existing = registry.findIndex(entry => entry.detector.name === name);
+    if (existing < 0) return false;
+    registry.splice(existing, 1);
+    return true;
+}
+
+/**
+ * Registered detectors in the order they run
+ */
+export function getDetectors(role?: Detector['role']): Detector[] {
+    return registry
+        .filter(entry => !role || entry.detector.role === role)
+        .sort((a, b) => (b.detector.priority || 0) - (a.detector.priority || 0) || a.order - b.order)
+        .map(entry => entry.detector);
+}
+
+export function findDetector(name: string): Detector | undefined {
+    return registry.find(entry => entry.detector.name === name)?.detector;
+}
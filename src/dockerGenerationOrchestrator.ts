@@ -1,16 +1,43 @@
 import * as fs from 'fs';
 import * as path from 'path';
-import * as vscode from 'vscode';
-import { EnhancedDetectionEngine, EnhancedDetectionResult } from './enhancedDetectionEngine';
-import { DeterministicDockerGenerator, DeterministicGenerationResult } from './deterministicDockerGenerator';
+import { EnhancedDetectionEngine, EnhancedDetectionResult, describeNoStack } from './enhancedDetectionEngine';
+import { DeterministicDockerGenerator, DeterministicGenerationResult, GenerationOptions } from './deterministicDockerGenerator';
+import { TemplateManager } from './templates/templateManager';
+import { WORKFLOW_PATH } from './templates/ci/workflowTemplateManager';
+import { ComposeTemplateManager } from './templates/compose/composeTemplateManager';
+import { loadProjectConfig } from './projectConfig';
+import { CACHE_FILE, IncrementalCache, CACHE_VERSION, hashContent, hashServiceInputs, loadIncrementalCache, serializeIncrementalCache } from './incrementalCache';
 
 export interface GeneratedDockerFiles {
     dockerfile?: string;
     dockerCompose: string;
+    dockerComposeOverride?: string;
     dockerIgnore: string;
     nginxConf?: string;
     frontendDockerfiles?: Array<{ path: string; content: string }>;
     backendDockerfiles?: Array<{ path: string; content: string }>;
+    serviceDockerIgnores?: Array<{ path: string; content: string }>;
+    serviceFiles?: Array<{ path: string; content: string }>;  // Extra files from custom detectors
+    envExample?: string;
+    githubWorkflow?: string;
+    makefile?: string;
+    kubernetesManifests?: Array<{ path: string; content: string }>;  // k8s/<service>.yaml
+    incrementalCache?: string;  // .autodocker.cache (incremental mode)
+}
+
+/**
+ * Minimal log sink (satisfied by vscode.OutputChannel and by the CLI)
+ */
+export interface GenerationLogger {
+    appendLine(value: string): void;
+}
+
+/**
+ * A single file the generator wants on disk, relative to the project root
+ */
+export interface OutputFile {
+    path: string;
+    content: string;
 }
 
 export interface GenerationResult {
@@ -20,6 +47,7 @@ export interface GenerationResult {
     deterministicResult?: DeterministicGenerationResult;
     warnings: string[];
     skipped: string[];
+    errors: Array<{ path: string; message: string }>; // Per-service detection/generation failures (sorted by path)
     usedDeterministic: boolean;
     architecture?: {
         containerCommunication: string;
@@ -36,21 +64,26 @@ export interface GenerationResult {
 export class DockerGenerationOrchestrator {
     private basePath: string;
     private detectionEngine: EnhancedDetectionEngine;
-    private outputChannel?: vscode.OutputChannel;
+    private outputChannel?: GenerationLogger;
+    private options: GenerationOptions;
+    private cache?: IncrementalCache;  // Previous run's .autodocker.cache (incremental mode only)
 
     constructor(
         basePath: string,
-        outputChannel?: vscode.OutputChannel
+        outputChannel?: GenerationLogger,
+        options: GenerationOptions = {}
     ) {
         this.basePath = basePath;
-        this.detectionEngine = new EnhancedDetectionEngine(basePath);
+        this.detectionEngine = new EnhancedDetectionEngine(basePath, { recursive: options.recursiveScan });
         this.outputChannel = outputChannel;
+        this.options = options;
     }
 
     /**
      * Main generation method using Deterministic Blueprint-Based Generation
+     * Pass a detection result to skip detection (library API)
      */
-    async generate(): Promise<GenerationResult> {
+    async generate(detected?: EnhancedDetectionResult): Promise<GenerationResult> {
         this.log('📐 Starting Deterministic Blueprint-Based Generation...');
 
         const warnings: string[] = [];
@@ -59,29 +92,57 @@ export class DockerGenerationOrchestrator {
         try {
             // Step 1: Detection
             this.log('🔍 Detecting project structure...');
-            const detectionResult = await this.detectionEngine.detect();
+            const detectionResult = detected || await this.detectionEngine.detect();
+            // RULE: Nothing detected is an error, never a guessed default stack
+            if (detectionResult.projectType === 'none') {
+                throw new Error(describeNoStack(this.basePath));
+            }
+            const unrecognized = detectionResult.monorepo?.unrecognized || [];
+            if (unrecognized.length > 0) {
+                warnings.push(`Skipped ${unrecognized.length} director${unrecognized.length === 1 ? 'y' : 'ies'} with no detectable stack: ${unrecognized.join(', ')}`);
+            }
+            this.cache = this.options.incremental ? loadIncrementalCache(this.basePath) : undefined;
 
             // Step 2: Generate using deterministic generator
+            // .autodocker.yaml base images are consulted before the public template defaults
             this.log('📝 Generating Docker files from blueprints...');
-            const generator = new DeterministicDockerGenerator(detectionResult);
+            // Resource limits: .autodocker.yaml `resources:` with option values merged over each entry
+            const projectConfig = loadProjectConfig(this.basePath);
+            const resources = { ...projectConfig.resources };
+            for (const [service, limits] of Object.entries(this.options.resourceLimits || {})) {
+                resources[service] = { ...resources[service], ...limits };
+            }
+            const generator = new DeterministicDockerGenerator(detectionResult, {
+                ...this.options,
+                baseImages: { ...projectConfig.images, ...this.options.baseImages },
+                templatesDir: this.options.templatesDir && path.resolve(this.basePath, this.options.templatesDir),
+                projectName: this.options.projectName || ComposeTemplateManager.getProjectName(this.basePath),
+                resourceLimits: this.options.resourceLimits || Object.keys(resources).length > 0 ? resources : undefined
+            });
             const result = await generator.generate();
 
             // Step 3: Convert to our file format
+            // .dockerignore files are merged with any existing ones, never clobbered
             const files: GeneratedDockerFiles = {
                 dockerCompose: result.files.dockerCompose,
-                dockerIgnore: result.files.dockerignore,
+                dockerIgnore: this.mergeWithExistingDockerignore('.dockerignore', result.files.dockerignore),
                 frontendDockerfiles: [],
-                backendDockerfiles: []
+                backendDockerfiles: [],
+                serviceDockerIgnores: result.files.serviceDockerignores.map(d => ({
+                    path: d.path,
+                    content: this.mergeWithExistingDockerignore(d.path, d.content)
+                }))
             };
 
             // Separate frontend and backend Dockerfiles
-            result.files.dockerfiles.forEach(df => {
+            // RULE: Existing Dockerfiles are hand-tuned - keep them unless overwriting was requested
+            result.files.dockerfiles.flatMap(df => this.resolveExistingDockerfile(df, skipped)).forEach(df => {
                 const isFrontend = df.path.includes('frontend') || df.path.includes('web') || df.path.includes('client');
                 const isBackend = df.path.includes('backend') || df.path.includes('server') || df.path.includes('api');
 
                 if (isFrontend) {
                     files.frontendDockerfiles!.push(df);
-                } else if (isBackend) {
+                } else if (isBackend || df.path !== 'Dockerfile') {
                     files.backendDockerfiles!.push(df);
                 } else {
                     // Root Dockerfile
@@ -89,10 +150,64 @@ export class DockerGenerationOrchestrator {
                 }
             });
 
+            // Custom detectors' extra files follow the same keep-existing rule as Dockerfiles
+            if (result.files.serviceFiles.length > 0) {
+                files.serviceFiles = result.files.serviceFiles.flatMap(f => this.resolveExistingDockerfile(f, skipped));
+            }
+
             if (result.files.nginxConf) {
                 files.nginxConf = result.files.nginxConf;
             }
 
+            if (result.files.envExample) {
+                files.envExample = this.mergeWithExistingEnvExample(result.files.envExample);
+            }
+
+            // Never replace a workflow the project already has
+            if (result.files.githubWorkflow) {
+                if (fs.existsSync(path.join(this.basePath, WORKFLOW_PATH))) {
+                    this.log(`⏭️  ${WORKFLOW_PATH} already exists - keeping it`);
+                    skipped.push(`${WORKFLOW_PATH} (already exists - kept as is)`);
+                } else {
+                    files.githubWorkflow = result.files.githubWorkflow;
+                }
+            }
+
+            // A hand-written override carries local settings - never replace it
+            if (result.files.dockerComposeOverride) {
+                if (fs.existsSync(path.join(this.basePath, 'docker-compose.override.yml'))) {
+                    this.log('⏭️  docker-compose.override.yml already exists - keeping it');
+                    skipped.push('docker-compose.override.yml (already exists - kept as is)');
+                } else {
+                    files.dockerComposeOverride = result.files.dockerComposeOverride;
+                }
+            }
+
+            // Projects often keep their own Makefile - never replace it
+            if (result.files.makefile) {
+                if (fs.existsSync(path.join(this.basePath, 'Makefile'))) {
+                    this.log('⏭️  Makefile already exists - keeping it');
+                    skipped.push('Makefile (already exists - kept as is)');
+                } else {
+                    files.makefile = result.files.makefile;
+                }
+            }
+
+            // Hand-edited manifests carry cluster settings - never replace them
+            if (result.files.kubernetesManifests) {
+                files.kubernetesManifests = result.files.kubernetesManifests.filter(m => {
+                    if (!fs.existsSync(path.join(this.basePath, m.path))) return true;
+                    this.log(`⏭️  ${m.path} already exists - keeping it`);
+                    skipped.push(`${m.path} (already exists - kept as is)`);
+                    return false;
+                });
+            }
+
+            // Incremental: leave unchanged services and identical files alone
+            if (this.cache) {
+                this.applyIncremental(files, detectionResult, skipped);
+            }
+
             // Log architecture
             this.log(`\n✅ Blueprint: ${result.blueprint.type}`);
             this.log(`📦 Services: ${result.architecture.services.join(', ')}`);
@@ -111,6 +226,14 @@ export class DockerGenerationOrchestrator {
                 warnings.push(...result.warnings);
             }
 
+            // Services that failed detection or generation were left out of every output
+            const errors = [...(detectionResult.monorepo?.errors || []), ...result.errors]
+                .sort((a, b) => a.path.localeCompare(b.path));
+            if (errors.length > 0) {
+                this.log('\n❌ Failed services:');
+                errors.forEach(e => this.log(`   - ${e.path}: ${e.message}`));
+            }
+
             return {
                 success: true,
                 files,
@@ -118,6 +241,7 @@ export class DockerGenerationOrchestrator {
                 deterministicResult: result,
                 warnings,
                 skipped,
+                errors,
                 usedDeterministic: true,
                 architecture: {
                     containerCommunication: result.architecture.topology,
@@ -134,6 +258,136 @@ export class DockerGenerationOrchestrator {
         }
     }
 
+    /**
+     * Apply the existingDockerfile policy to one generated Dockerfile
+     * skip: drop it and record it as skipped; generated: write <path>.generated; overwrite: write as is
+     */
+    private resolveExistingDockerfile(df: OutputFile, skipped: string[]): OutputFile[] {
+        const policy = this.options.existingDockerfile || 'skip';
+        if (policy === 'overwrite' || !fs.existsSync(path.join(this.basePath, df.path)) || this.isCachedOutput(df.path)) {
+            return [df];
+        }
+
+        if (policy === 'generated') {
+            this.log(`📄 ${df.path} already exists - writing ${df.path}.generated for comparison`);
+            return [{ path: `${df.path}.generated`, content: df.content }];
+        }
+
+        this.log(`⏭️  ${df.path} already exists - keeping it`);
+        skipped.push(`${df.path} (already exists - kept as is)`);
+        return [];
+    }
+
+    /**
+     * Incremental mode: drop the files of services whose inputs are unchanged, and any file
+     * whose content on disk is already identical, then emit the updated cache
+     * RULE: A service counts as unchanged only while every file cached for it is untouched on disk
+     */
+    private applyIncremental(files: GeneratedDockerFiles, detection: EnhancedDetectionResult, skipped: string[]): void {
+        const services = [
+            ...(detection.monorepo?.frontends || (detection.frontend ? [detection.frontend] : [])),
+            ...(detection.monorepo?.backends || (detection.backend ? [detection.backend] : []))
+        ];
+        const next: IncrementalCache = { version: CACHE_VERSION, services: {} };
+        const unchanged = new Set<string>();
+
+        for (const service of services) {
+            if (next.services[service.path]) continue;
+            const inputs = hashServiceInputs(this.basePath, service, this.options);
+            const previous = this.cache?.services[service.path];
+            if (previous && previous.inputs === inputs
+                && Object.entries(previous.files).every(([file, hash]) => this.matchesOnDisk(file, hash))) {
+                unchanged.add(service.path);
+                next.services[service.path] = previous;
+                this.log(`⏭️  ${service.path}: inputs unchanged since the last run - not regenerated`);
+                skipped.push(`${service.path} (inputs unchanged - not regenerated)`);
+            } else {
+                next.services[service.path] = { inputs, files: {} };
+            }
+        }
+
+        // Service files belong to the deepest service directory containing them; the root service comes last
+        const ownerPaths = Object.keys(next.services)
+            .sort((a, b) => (a === '.' ? 1 : 0) - (b === '.' ? 1 : 0) || b.length - a.length);
+        const ownerOf = (file: string) => ownerPaths.find(p => p === '.' || file.startsWith(`${p}/`));
+        const keepServiceFile = (f: OutputFile) => {
+            const owner = ownerOf(f.path);
+            if (owner && unchanged.has(owner)) return false;
+            if (owner) next.services[owner].files[f.path] = hashContent(f.content);
+            return this.keepIfChanged(f.path, f.content, skipped);
+        };
+        const keepShared = (file: string, content?: string) =>
+            content !== undefined && this.keepIfChanged(file, content, skipped) ? content : undefined;
+
+        // nginx.conf is only written at the root when no frontend Dockerfile is; decide before filtering them
+        const nginxAtRoot = !files.frontendDockerfiles || files.frontendDockerfiles.length === 0;
+        if (files.dockerfile !== undefined && !keepServiceFile({ path: 'Dockerfile', content: files.dockerfile })) {
+            files.dockerfile = undefined;
+        }
+        files.frontendDockerfiles = files.frontendDockerfiles?.filter(keepServiceFile);
+        files.backendDockerfiles = files.backendDockerfiles?.filter(keepServiceFile);
+        files.serviceFiles = files.serviceFiles?.filter(keepServiceFile);
+        files.serviceDockerIgnores = files.serviceDockerIgnores?.filter(keepServiceFile);
+
+        files.dockerCompose = keepShared('docker-compose.yml', files.dockerCompose) || '';
+        files.dockerIgnore = keepShared('.dockerignore', files.dockerIgnore) || '';
+        files.dockerComposeOverride = keepShared('docker-compose.override.yml', files.dockerComposeOverride);
+        files.nginxConf = nginxAtRoot ? keepShared('nginx.conf', files.nginxConf) : undefined;
+        files.envExample = keepShared('.env.example', files.envExample);
+        files.githubWorkflow = keepShared(WORKFLOW_PATH, files.githubWorkflow);
+        files.makefile = keepShared('Makefile', files.makefile);
+        files.kubernetesManifests = files.kubernetesManifests?.filter(m => this.keepIfChanged(m.path, m.content, skipped));
+
+        const cache = serializeIncrementalCache(next);
+        files.incrementalCache = this.readExisting(CACHE_FILE) === cache ? undefined : cache;
+    }
+
+    /**
+     * Whether a file on disk is still exactly what an earlier incremental run wrote
+     */
+    private isCachedOutput(relativePath: string): boolean {
+        return Object.values(this.cache?.services || {})
+            .some(entry => entry.files[relativePath] !== undefined && this.matchesOnDisk(relativePath, entry.files[relativePath]));
+    }
+
+    private matchesOnDisk(relativePath: string, hash: string): boolean {
+        const existing = this.readExisting(relativePath);
+        return existing !== undefined && hashContent(existing) === hash;
+    }
+
+    private keepIfChanged(relativePath: string, content: string, skipped: string[]): boolean {
+        if (this.readExisting(relativePath) !== content) return true;
+        skipped.push(`${relativePath} (unchanged)`);
+        return false;
+    }
+
+    private readExisting(relativePath: string): string | undefined {
+        const filePath = path.join(this.basePath, relativePath);
+        return fs.existsSync(filePath) ? fs.readFileSync(filePath, 'utf-8') : undefined;
+    }
+
+    /**
+     * Merge generated .dockerignore content into the file already on disk (if any)
+     */
+    private mergeWithExistingDockerignore(relativePath: string, content: string): string {
+        const filePath = path.join(this.basePath, relativePath);
+        if (!fs.existsSync(filePath)) {
+            return content;
+        }
+        return TemplateManager.mergeDockerignore(fs.readFileSync(filePath, 'utf-8'), content);
+    }
+
+    /**
+     * Merge generated .env.example entries into the file already on disk (if any)
+     */
+    private mergeWithExistingEnvExample(content: string): string {
+        const filePath = path.join(this.basePath, '.env.example');
+        if (!fs.existsSync(filePath)) {
+            return content;
+        }
+        return TemplateManager.mergeEnvExample(fs.readFileSync(filePath, 'utf-8'), content);
+    }
+
     private log(message: string) {
         if (this.outputChannel) {
             this.outputChannel.appendLine(message);
@@ -141,11 +395,76 @@ export class DockerGenerationOrchestrator {
         console.log(`[Orchestrator] ${message}`);
     }
 
+    /**
+     * Flatten generated files into the exact list of paths and contents to write
+     * RULE: Real writes and --dry-run previews both go through this list
+     */
+    static getOutputFiles(files: GeneratedDockerFiles): OutputFile[] {
+        const outputs: OutputFile[] = [];
+
+        if (files.dockerfile) {
+            outputs.push({ path: 'Dockerfile', content: files.dockerfile });
+        }
+        outputs.push(...(files.frontendDockerfiles || []));
+        outputs.push(...(files.backendDockerfiles || []));
+        outputs.push(...(files.serviceFiles || []));
+
+        if (files.dockerCompose) {
+            outputs.push({ path: 'docker-compose.yml', content: files.dockerCompose });
+        }
+        if (files.dockerComposeOverride) {
+            outputs.push({ path: 'docker-compose.override.yml', content: files.dockerComposeOverride });
+        }
+
+        // nginx.conf at root ONLY for single-service frontend projects
+        if (files.nginxConf && (!files.frontendDockerfiles || files.frontendDockerfiles.length === 0)) {
+            outputs.push({ path: 'nginx.conf', content: files.nginxConf });
+        }
+
+        // .dockerignore files are already merged with any existing ones
+        if (files.dockerIgnore) {
+            outputs.push({ path: '.dockerignore', content: files.dockerIgnore });
+        }
+        outputs.push(...(files.serviceDockerIgnores || []));
+
+        if (files.envExample) {
+            outputs.push({ path: '.env.example', content: files.envExample });
+        }
+
+        if (files.githubWorkflow) {
+            outputs.push({ path: WORKFLOW_PATH, content: files.githubWorkflow });
+        }
+
+        if (files.makefile) {
+            outputs.push({ path: 'Makefile', content: files.makefile });
+        }
+        outputs.push(...(files.kubernetesManifests || []));
+
+        if (files.incrementalCache) {
+            outputs.push({ path: CACHE_FILE, content: files.incrementalCache });
+        }
+
+        return outputs;
+    }
+
+    /**
+     * Write output files under basePath, creating directories as needed
+     */
+    static writeOutputFiles(basePath: string, outputs: OutputFile[], logger?: GenerationLogger): void {
+        for (const f of outputs) {
+            const filePath = path.join(basePath, f.path);
+            const dir = path.dirname(filePath);
+            if (!fs.existsSync(dir)) fs.mkdirSync(dir, { recursive: true });
+            fs.writeFileSync(filePath, f.content, 'utf-8');
+            logger?.appendLine(`✅ Written: ${f.path}`);
+        }
+    }
+
     /**
      * Get user-friendly summary
      */
     static generateSummary(result: GenerationResult): string {
-        const { deterministicResult, files, warnings, skipped, architecture, assumptions } = result;
+        const { deterministicResult, files, warnings, skipped, errors, architecture, assumptions } = result;
 
         let summary = `## Docker Configuration Generated\n\n`;
         summary += `**Generation Method:** 📐 Deterministic Blueprint-Based\n\n`;
@@ -163,6 +482,12 @@ export class DockerGenerationOrchestrator {
             summary += `- **Build/Runtime:** ${architecture.buildRuntimeSeparation}\n\n`;
         }
 
+        // Per-directory detection (monorepo / recursive scan)
+        if (result.detectionResult?.monorepo) {
+            summary += `### Detected Services\n`;
+            summary += this.formatServiceTable(result.detectionResult) + '\n';
+        }
+
         // Generated files
         summary += `### Generated Files\n`;
 
@@ -179,12 +504,42 @@ export class DockerGenerationOrchestrator {
                 summary += `- ✅ ${f.path}\n`;
             }
         }
-        summary += `- ✅ docker-compose.yml\n`;
-        summary += `- ✅ .dockerignore\n`;
+        for (const f of files.serviceFiles || []) {
+            summary += `- ✅ ${f.path}\n`;
+        }
+        if (files.dockerCompose) {
+            summary += `- ✅ docker-compose.yml\n`;
+        }
+        if (files.dockerComposeOverride) {
+            summary += `- ✅ docker-compose.override.yml\n`;
+        }
+        if (files.dockerIgnore) {
+            summary += `- ✅ .dockerignore\n`;
+        }
+        if (files.serviceDockerIgnores && files.serviceDockerIgnores.length > 0) {
+            for (const f of files.serviceDockerIgnores) {
+                summary += `- ✅ ${f.path}\n`;
+            }
+        }
 
         if (files.nginxConf) {
             summary += `- ✅ nginx.conf\n`;
         }
+        if (files.envExample) {
+            summary += `- ✅ .env.example\n`;
+        }
+        if (files.githubWorkflow) {
+            summary += `- ✅ ${WORKFLOW_PATH}\n`;
+        }
+        if (files.makefile) {
+            summary += `- ✅ Makefile\n`;
+        }
+        for (const f of files.kubernetesManifests || []) {
+            summary += `- ✅ ${f.path}\n`;
+        }
+        if (files.incrementalCache) {
+            summary += `- ✅ ${CACHE_FILE}\n`;
+        }
 
         // Assumptions
         if (assumptions && assumptions.length > 0) {
@@ -210,9 +565,42 @@ export class DockerGenerationOrchestrator {
             }
         }
 
+        // Failed services (not included in any generated file)
+        if (errors.length > 0) {
+            summary += `\n### Errors\n`;
+            for (const error of errors) {
+                summary += `- ❌ ${error.path}: ${error.message}\n`;
+            }
+        }
+
         return summary;
     }
 
+    /**
+     * Markdown table of which directory got which stack
+     */
+    static formatServiceTable(detection: EnhancedDetectionResult): string {
+        const rows: string[][] = [];
+        const monorepo = detection.monorepo;
+
+        for (const f of monorepo?.frontends || []) {
+            rows.push([f.path, 'frontend', f.framework]);
+        }
+        for (const b of monorepo?.backends || []) {
+            rows.push([b.path, 'backend', `${b.language} (${b.framework})`]);
+        }
+        for (const dir of monorepo?.unrecognized || []) {
+            rows.push([dir, '-', 'not detected']);
+        }
+        rows.sort((a, b) => a[0].localeCompare(b[0]));
+
+        let table = `| Directory | Role | Stack |\n|-----------|------|-------|\n`;
+        for (const [dir, role, stack] of rows) {
+            table += `| ${dir} | ${role} | ${stack} |\n`;
+        }
+        return table;
+    }
+
     /**
      * Check for existing files and determine conflicts
      */
@@ -0,0 +1,217 @@
+/**
+ * Dockerfile Linter
+ *
+ * Hadolint-style checks run on every generated Dockerfile before it is written.
+ * RULE: Pure function of the Dockerfile content - no file IO, no docker daemon.
+ */
+
+export type FindingSeverity = 'error' | 'warning';
+
+export interface Finding {
+    rule: string;              // Hadolint id where one exists (DL3007), AD### for Auto Docker's own rules
+    severity: FindingSeverity;
+    line: number;              // 1-based line of the instruction (first line when continued with \)
+    message: string;
+}
+
+interface Instruction {
+    keyword: string;  // Upper-cased instruction, e.g. COPY
+    args: string;     // Everything after the keyword, continuation lines joined
+    line: number;
+}
+
+// Commands that install dependencies from a manifest; COPY . . before them rebuilds the layer on every source change
+const DEPENDENCY_INSTALLS = [
+    /\bnpm (ci|install)\b/, /\byarn install\b/, /\bpnpm install\b/,
+    /\bpip install\b.*-r\b/, /\bpoetry install\b/, /\bpipenv install\b/,
+    /\bgo mod download\b/, /\bbundle install\b/, /\bcomposer install\b/,
+    /\bcargo fetch\b/, /\bmvn\b.*dependency:/, /\bgradle dependencies\b/, /\bdotnet restore\b/, /\bmix deps\.get\b/
+];
+
+// Base images without /bin/sh - shell-form RUN/CMD/ENTRYPOINT/HEALTHCHECK cannot start there
+const SHELLLESS_IMAGE = /^(scratch|gcr\.io\/distroless\/(?!.*:debug)\S+)$/;
+
+// distroless names carry the version (static-debian12, java21-debian12); its tags only pick nonroot/debug
+const VERSIONED_IMAGE_NAME = /^gcr\.io\/distroless\/[\w-]+-debian\d+$/;
+
+/**
+ * Lint one Dockerfile
+ * Findings come back in line order.
+ */
+export function lintDockerfile(content: string | Buffer): Finding[] {
+    const instructions = parseInstructions(typeof content === 'string' ? content : content.toString('utf-8'));
+    const findings: Finding[] = [];
+    const add = (instruction: Instruction, rule: string, severity: FindingSeverity, message: string) =>
+        findings.push({ rule, severity, line: instruction.line, message });
+
+    const stages: string[] = [];   // Stage aliases declared so far (FROM ... AS name)
+    const shellless = new Set<string>();  // Stage aliases built on a shell-less image
+    let noShell = false;           // Current stage has no /bin/sh
+    const buildArgs = new Set<string>();
+    let workdirSet = false;
+    let sourceCopied = false;      // COPY . . seen in the current stage
+    let lastUser: Instruction | undefined;
+
+    if (!instructions.some(i => i.keyword === 'FROM')) {
+        findings.push({ rule: 'AD000', severity: 'error', line: 1, message: 'No FROM instruction' });
+    }
+
+    for (const instruction of instructions) {
+        const { keyword, args } = instruction;
+
+        switch (keyword) {
+            case 'ARG':
+                buildArgs.add(args.split('=')[0].trim());
+                break;
+
+            case 'FROM': {
+                const [image, asKeyword, alias] = args.split(/\s+/).filter(a => !a.startsWith('--'));
+                const isStage = stages.includes(image) || image === 'scratch' || image.startsWith('$');
+                if (!isStage && /:latest$/.test(image)) {
+                    add(instruction, 'DL3007', 'warning', `${image}: pin a version instead of :latest`);
+                } else if (!isStage && !image.includes(':') && !image.includes('@') && !VERSIONED_IMAGE_NAME.test(image)) {
+                    add(instruction, 'DL3006', 'warning', `${image}: always tag the image version`);
+                }
+                noShell = SHELLLESS_IMAGE.test(image) || shellless.has(image);
+                if (asKeyword && asKeyword.toUpperCase() === 'AS' && alias) {
+                    if (stages.includes(alias)) {
+                        add(instruction, 'DL3024', 'error', `Stage name "${alias}" is used more than once`);
+                    }
+                    stages.push(alias);
+                    if (noShell) shellless.add(alias);
+                }
+                sourceCopied = false;
+                workdirSet = false;
+                break;
+            }
+
+            case 'WORKDIR':
+                if (!args.startsWith('/') && !args.startsWith('$')) {
+                    add(instruction, 'DL3000', 'warning', `WORKDIR ${args}: use an absolute path`);
+                }
+                workdirSet = true;
+                break;
+
+            case 'MAINTAINER':
+                add(instruction, 'DL4000', 'warning', 'MAINTAINER is deprecated; use LABEL maintainer=...');
+                break;
+
+            case 'ADD':
+                if (!/https?:\/\//.test(args) && !/\.(tar|tar\.gz|tgz|tar\.bz2|tar\.xz)\b/.test(args)) {
+                    add(instruction, 'DL3020', 'warning', 'Use COPY instead of ADD for files and folders');
+                }
+                break;
+
+            case 'COPY': {
+                const from = args.match(/--from=(\S+)/);
+                if (from && !stages.includes(from[1]) && !/^\d+$/.test(from[1]) && !from[1].includes('/') && !from[1].includes(':')) {
+                    add(instruction, 'DL3022', 'error', `COPY --from=${from[1]} does not name an earlier stage`);
+                }
+                const paths = args.split(/\s+/).filter(a => !a.startsWith('--'));
+                const dest = paths[paths.length - 1] || '';
+                if (!workdirSet && !dest.startsWith('/') && !dest.startsWith('$')) {
+                    add(instruction, 'DL3045', 'warning', `COPY to relative path ${dest} without a WORKDIR`);
+                }
+                if (!from && paths.length === 2 && paths[0] === '.') {
+                    sourceCopied = true;
+                }
+                break;
+            }
+
+            case 'RUN':
+                if (noShell && !args.startsWith('[')) {
+                    add(instruction, 'AD002', 'error', 'RUN: shell form needs /bin/sh, which this base image does not have');
+                }
+                if (sourceCopied && DEPENDENCY_INSTALLS.some(re => re.test(args))) {
+                    add(instruction, 'AD001', 'warning', 'Dependencies are installed after COPY . . - copy the manifest first so this layer stays cached');
+                }
+                if (/\bapt\s+(install|get)\b/.test(args) && !/\bapt-get\b/.test(args)) {
+                    add(instruction, 'DL3027', 'warning', 'Use apt-get or apt-cache instead of apt');
+                }
+                if (/\bapt-get\s+(-\S+\s+)*install\b/.test(args) && !args.includes('--no-install-recommends')) {
+                    add(instruction, 'DL3015', 'warning', 'apt-get install: add --no-install-recommends');
+                }
+                if (/\bapt-get\s+(-\S+\s+)*install\b/.test(args) && !args.includes('rm -rf /var/lib/apt/lists')) {
+                    add(instruction, 'DL3009', 'warning', 'Delete /var/lib/apt/lists after apt-get install');
+                }
+                if (/\bapk\s+add\b/.test(args) && !args.includes('--no-cache')) {
+                    add(instruction, 'DL3018', 'warning', 'apk add: use --no-cache');
+                }
+                if (/\bpip3?\s+install\b/.test(args) && !args.includes('--no-cache-dir')) {
+                    add(instruction, 'DL3042', 'warning', 'pip install: use --no-cache-dir');
+                }
+                if (/\bsudo\b/.test(args)) {
+                    add(instruction, 'DL3004', 'warning', 'Do not use sudo; RUN already runs as the current USER');
+                }
+                if (/^cd\s/.test(args)) {
+                    add(instruction, 'DL3003', 'warning', 'Use WORKDIR to switch to a directory');
+                }
+                break;
+
+            case 'CMD':
+            case 'ENTRYPOINT':
+                if (noShell && !args.startsWith('[')) {
+                    add(instruction, 'AD002', 'error', `${keyword}: shell form needs /bin/sh, which this base image does not have`);
+                } else if (!args.startsWith('[')) {
+                    add(instruction, 'DL3025', 'warning', `${keyword}: use the JSON (exec) form so signals reach the process`);
+                }
+                break;
+
+            case 'HEALTHCHECK': {
+                const command = args.match(/(?:^|\s)CMD\s+(.*)$/i);
+                if (noShell && command && !command[1].startsWith('[')) {
+                    add(instruction, 'AD002', 'error', 'HEALTHCHECK: shell form needs /bin/sh, which this base image does not have');
+                }
+                break;
+            }
+
+            case 'EXPOSE':
+                for (const port of args.split(/\s+/)) {
+                    const match = port.match(/^\$\{?(\w+)\}?$/);
+                    if (match ? !buildArgs.has(match[1]) : !/^\d+(\/(tcp|udp))?$/.test(port)) {
+                        add(instruction, 'DL3011', 'error', `EXPOSE ${port}: not a port number or declared ARG`);
+                    } else if (!match && Number(port.split('/')[0]) > 65535) {
+                        add(instruction, 'DL3011', 'error', `EXPOSE ${port}: valid UNIX ports range from 0 to 65535`);
+                    }
+                }
+                break;
+
+            case 'USER':
+                lastUser = instruction;
+                break;
+        }
+    }
+
+    if (lastUser && /^(root|0)(:|$)/.test(lastUser.args)) {
+        add(lastUser, 'DL3002', 'warning', 'Last USER should not be root');
+    }
+
+    return findings.sort((a, b) => a.line - b.line);
+}
+
+/**
+ * Split a Dockerfile into instructions, joining `\` continuations and dropping comments
+ */
+function parseInstructions(content: string): Instruction[] {
+    const instructions: Instruction[] = [];
+    const lines = content.split('\n');
+
+    for (let i = 0; i < lines.length; i++) {
+        const start = i;
+        let text = lines[i].trim();
+        if (!text || text.startsWith('#')) continue;
+
+        while (text.endsWith('\\') && i + 1 < lines.length) {
+            i++;
+            const next = lines[i].trim();
+            text = text.slice(0, -1).trimEnd() + (next.startsWith('#') ? '' : ' ' + next);
+        }
+
+        const match = text.match(/^(\w+)\s*(.*)$/);
+        if (match) {
+            instructions.push({ keyword: match[1].toUpperCase(), args: match[2].trim(), line: start + 1 });
+        }
+    }
+
+    return instructions;
+}
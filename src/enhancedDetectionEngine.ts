@@ -6,6 +6,14 @@
 
 import * as fs from 'fs';
 import * as path from 'path';
+import { KNOWN_STACKS, StackKey } from './projectConfig';
+import { defaultConcurrency, mapWithConcurrency } from './concurrency';
+import { GitInfo, readGitInfo } from './gitInfo';
+import { DetectedService, Detector, DetectorContext, getDetectors, registerDetector } from './detectorRegistry';
+import { createLogger } from './logger';
+
+const log = createLogger('EnhancedDetectionEngine');
+const deepScanLog = createLogger('DeepScan');
 
 export interface FrameworkOutputInfo {
     framework: string;
@@ -20,7 +28,8 @@ export type ProjectType =
     | 'frontend-only'
     | 'backend-only'
     | 'fullstack'
-    | 'monorepo';
+    | 'monorepo'
+    | 'none';      // No detectable stack - generation refuses it (see describeNoStack)
 
 export interface DetectedFrontend {
     exists: boolean;
@@ -33,6 +42,24 @@ export interface DetectedFrontend {
     path: string; // Relative path in monorepo (or "." for single projects)
     port?: number;
     projectPath?: string; // Absolute path to project root
+    envVars?: string[]; // Environment variables referenced in source (sorted, de-duplicated)
+    devProxy?: { path?: string; target: string }; // Dev-server API proxy (package.json "proxy", vite server.proxy, vue devServer.proxy)
+    hasDockerfile?: boolean; // A Dockerfile is already in the service directory
+    dockerfilePort?: number; // EXPOSE of a Dockerfile already in the service directory
+    hotReload?: HotReload; // Dev server found in package.json scripts
+    detector?: string; // Registered detector that found it (see detectorRegistry.ts)
+    privateDependencies?: PrivateDependencies; // npm packages behind registry credentials
+    apiUrlEnvs?: ApiUrlEnv[]; // API base URL variables from source and .env files, browser-exposed ones first
+}
+
+/**
+ * An environment variable a frontend reads its API base URL from
+ */
+export interface ApiUrlEnv {
+    name: string;       // e.g. VITE_API_URL
+    browser: boolean;   // Carries the framework's public prefix, so it reaches code running in the browser
+    value?: string;     // Local development value from a .env file (e.g. http://localhost:8080/api)
+    file?: string;      // .env file the value came from
 }
 
 export interface DetectedBackend {
@@ -42,9 +69,66 @@ export interface DetectedBackend {
     packageManager?: string;
     path: string; // Relative path in monorepo (or "." for single projects)
     port?: number;
+    ports?: number[]; // All detected listening ports (first one is the primary port)
     dependencies?: any;
-    entryPoint?: string; // Main entry file (e.g., server.js, index.js)
+    entryPoint?: string; // Main entry file (e.g., server.js, index.js), Go build target (e.g., ./cmd/server) or .NET project to publish
     projectPath?: string; // Absolute path to project root
+    languageVersion?: string; // Toolchain version declared by the project (e.g., go directive in go.mod)
+    dependencyFile?: string; // Dependency manifest to install from (e.g., requirements.txt, pyproject.toml)
+    lockFile?: string; // Dependency lock file copied alongside the manifest (e.g., go.sum)
+    asgiApp?: string; // ASGI application for uvicorn (e.g., main:app)
+    healthCheckPath?: string; // HTTP path probed by the container HEALTHCHECK
+    envVars?: string[]; // Environment variables referenced in source (sorted, de-duplicated)
+    binaryName?: string; // Compiled binary produced by the build (e.g., Cargo package name, .NET assembly name)
+    vendored?: boolean; // Go modules vendored in vendor/ (vendor/modules.txt present)
+    tlsClient?: string; // Why the Go binary needs CA certificates, e.g. "http.Get in client.go"
+    goWorkspace?: GoWorkspace; // Member of the root go.work - built from the workspace root
+    dockerfilePort?: number; // EXPOSE of a Dockerfile already in the service directory
+    hasDockerfile?: boolean; // A Dockerfile is already in the service directory
+    dockerfileCopiesGoWork?: boolean; // That Dockerfile copies go.work - it builds from the workspace root
+    hotReload?: HotReload; // Watcher declared by the project (air, nodemon, ...)
+    detector?: string; // Registered detector that found it (see detectorRegistry.ts)
+    routes?: string[]; // Registered HTTP routes, e.g. "GET /api/users" (Gin/Echo)
+    entryPoints?: string[]; // Every Go main package (build targets like ./cmd/server) when there is more than one
+    hasBuildScript?: boolean; // package.json has a build script (Node) - run in the builder stage
+    privateDependencies?: PrivateDependencies; // Go modules or npm packages behind credentials
+    buildTags?: GoBuildTags; // Custom //go:build tags in the module's source
+    servesFrontend?: string; // Frontend framework in the same package.json, built and served by this Node server
+}
+
+/**
+ * Go workspace declared by go.work at the project root
+ */
+export interface GoWorkspace {
+    modules: string[];   // Module directories from `use` directives, relative to the root ("." for the root module)
+    goVersion?: string;  // go directive of go.work
+}
+
+/**
+ * Custom build tags of a Go module (target and toolchain tags like linux or cgo are not custom)
+ */
+export interface GoBuildTags {
+    required: Record<string, string[]>;  // Build target (., ./cmd/server) => tags its func main only builds with
+    optional: string[];                  // Tags that only add files - the build leaves them out
+    ambiguous?: string;                  // Why the tags to build with can't be told from the constraints
+}
+
+/**
+ * Dependencies that need credentials to install, passed as a BuildKit secret
+ */
+export interface PrivateDependencies {
+    secret: 'netrc' | 'npmrc';  // Secret id; mounted at /root/.<id> for the install step only
+    goPrivate?: string;          // GOPRIVATE/GONOSUMDB module path patterns (comma-separated hosts)
+    reason: string;              // e.g. "go.mod requires git.corp.example/platform/auth"
+}
+
+/**
+ * Hot-reload tooling a service already declares, run by docker-compose.override.yml
+ */
+export interface HotReload {
+    tool: string;       // Watcher or dev server, e.g. air, nodemon, vite
+    command: string[];  // Run in the builder stage with the source mounted over /app
+    port?: number;      // Port the dev server listens on (frontends)
 }
 
 export interface DetectedDatabase {
@@ -56,10 +140,12 @@ export interface DetectedDatabase {
 
 export interface MonorepoInfo {
     isMonorepo: boolean;
-    tool?: 'yarn' | 'pnpm' | 'lerna' | 'nx' | 'turbo' | 'rush';
+    tool?: 'yarn' | 'pnpm' | 'lerna' | 'nx' | 'turbo' | 'rush' | 'go-work';
     workspaces?: string[];
     frontends: DetectedFrontend[];
     backends: DetectedBackend[];
+    unrecognized?: string[]; // Workspaces / recursive-scan project roots with no detectable stack (skipped)
+    errors?: Array<{ path: string; message: string }>; // Services whose detection failed (sorted by path)
 }
 
 export interface EnhancedDetectionResult {
@@ -74,23 +160,302 @@ export interface EnhancedDetectionResult {
     isMonorepo?: boolean;
     envFiles?: string[];
     envVars?: string[];
+    git?: GitInfo;  // Remote URL and HEAD commit when the project is inside a git repository
+}
+
+export interface DetectionOptions {
+    recursive?: boolean; // Walk the whole tree and treat every project root as a service
+    stack?: StackKey;    // Skip auto-detection and treat the target directory as this stack
+    concurrency?: number; // Max services scanned at once (default: one per CPU)
+}
+
+/**
+ * Frontend dev servers recognised in package.json scripts, with their default ports
+ */
+const FRONTEND_DEV_SERVERS: Array<{ tool: string; pattern: RegExp; port: number; needsHost: boolean }> = [
+    { tool: 'vite', pattern: /\bvite\b(?!\s+(build|preview)\b)/, port: 5173, needsHost: true },
+    { tool: 'next', pattern: /\bnext dev\b/, port: 3000, needsHost: false },
+    { tool: 'nuxt', pattern: /\bnuxi? dev\b/, port: 3000, needsHost: true },
+    { tool: 'react-scripts', pattern: /\breact-scripts start\b/, port: 3000, needsHost: false },
+    { tool: 'vue-cli-service', pattern: /\bvue-cli-service serve\b/, port: 8080, needsHost: true },
+    { tool: 'ng serve', pattern: /\bng serve\b/, port: 4200, needsHost: true },
+    { tool: 'astro', pattern: /\bastro dev\b/, port: 4321, needsHost: true },
+    { tool: 'webpack-dev-server', pattern: /\bwebpack(-dev-server|\s+serve)\b/, port: 8080, needsHost: true }
+];
+
+/**
+ * Go source that makes outbound TLS connections - a scratch image then needs CA certificates
+ */
+const GO_TLS_CLIENT_PATTERNS = [
+    /"(crypto\/tls)"/,
+    /\b(http\.(?:Get|Post|PostForm|Head|NewRequest|NewRequestWithContext))\(/,
+    /\b(http\.(?:Client\s*\{|DefaultClient\b))/,
+    /"(google\.golang\.org\/grpc\/credentials)"/,
+    /"((?:github\.com\/aws\/aws-sdk-go(?:-v2)?|cloud\.google\.com\/go|github\.com\/stripe\/stripe-go)\b[^"]*)"/,
+    /"(https:\/\/[^"\s]*)"/
+];
+
+/**
+ * Git hosts that serve public Go modules (their self-hosted namesakes are private)
+ */
+const PUBLIC_GIT_HOSTS = ['github.com', 'gitlab.com', 'bitbucket.org', 'codeberg.org', 'gitea.com', 'git.sr.ht', 'go.googlesource.com'];
+
+/**
+ * Host labels that mark a module host as private: git.internal.example.com, gitlab.corp, ...
+ */
+const PRIVATE_HOST_LABELS = ['internal', 'corp', 'intranet', 'private', 'local', 'lan'];
+
+/**
+ * First host labels of self-hosted forges: gitlab.example.com, github.example.com (Enterprise), git.example.com
+ */
+const SELF_HOSTED_FORGE_LABELS = ['git', 'gitlab', 'github', 'bitbucket', 'gitea', 'gerrit', 'scm'];
+
+/**
+ * npm registries that need no credentials
+ */
+const PUBLIC_NPM_REGISTRIES = ['registry.npmjs.org', 'registry.yarnpkg.com', 'registry.npmmirror.com'];
+
+/**
+ * Variable names that hold an API base URL: VITE_API_URL, NEXT_PUBLIC_API_BASE_URL, REACT_APP_BACKEND_URL, ...
+ */
+const API_URL_ENV = /(?:^|_)(?:API|BACKEND|SERVER)_(?:\w+_)?(?:URL|URI|BASE|HOST|ENDPOINT|ORIGIN)$/;
+
+/**
+ * .env files a frontend keeps its local settings in, in the order their values are preferred
+ */
+const FRONTEND_ENV_FILES = ['.env.development', '.env.local', '.env', '.env.example', '.env.sample'];
+
+/**
+ * //go:build identifiers that describe the target or toolchain, valued for the generated image:
+ * GOOS=linux, CGO disabled, gc; every architecture matches since builds may target several
+ */
+const GO_BUILTIN_TAGS: Record<string, boolean> = {
+    linux: true, unix: true, gc: true, gccgo: false, cgo: false, ignore: false,
+    ...Object.fromEntries(['aix', 'android', 'darwin', 'dragonfly', 'freebsd', 'hurd', 'illumos', 'ios', 'js', 'nacl',
+        'netbsd', 'openbsd', 'plan9', 'solaris', 'wasip1', 'windows', 'zos'].map(os => [os, false])),
+    ...Object.fromEntries(['386', 'amd64', 'arm', 'arm64', 'loong64', 'mips', 'mipsle', 'mips64', 'mips64le',
+        'ppc64', 'ppc64le', 'riscv64', 's390x', 'wasm'].map(arch => [arch, true]))
+};
+
+/**
+ * Parsed //go:build expression
+ */
+interface GoBuildConstraint {
+    matches: (tags: Set<string>) => boolean;  // Whether the file builds with these custom tags
+    positive: string[];                       // Custom tags the expression asks for
+    negative: string[];                       // Custom tags it excludes (!tag)
+}
+
+/**
+ * Parse a //go:build expression (&&, ||, ! and parentheses); undefined when it is malformed
+ */
+function parseGoBuildConstraint(expression: string): GoBuildConstraint | undefined {
+    const tokens = expression.trim().match(/&&|\|\||[!()]|[\w.]+/g) || [];
+    const positive = new Set<string>();
+    const negative = new Set<string>();
+    let i = 0;
+
+    type Matcher = (tags: Set<string>) => boolean;
+    const parseOr = (negated: boolean): Matcher => {
+        let left = parseAnd(negated);
+        while (tokens[i] === '||') {
+            i++;
+            const [a, b] = [left, parseAnd(negated)];
+            left = tags => a(tags) || b(tags);
+        }
+        return left;
+    };
+    const parseAnd = (negated: boolean): Matcher => {
+        let left = parseUnary(negated);
+        while (tokens[i] === '&&') {
+            i++;
+            const [a, b] = [left, parseUnary(negated)];
+            left = tags => a(tags) && b(tags);
+        }
+        return left;
+    };
+    const parseUnary = (negated: boolean): Matcher => {
+        const token = tokens[i++];
+        if (token === '!') {
+            const operand = parseUnary(!negated);
+            return tags => !operand(tags);
+        }
+        if (token === '(') {
+            const inner = parseOr(negated);
+            if (tokens[i++] !== ')') throw new Error('unbalanced parentheses');
+            return inner;
+        }
+        if (!token || !/^[\w.]+$/.test(token)) throw new Error(`unexpected ${token || 'end of expression'}`);
+        if (Object.hasOwn(GO_BUILTIN_TAGS, token) || /^go1\.\d+$/.test(token)) {
+            const value = GO_BUILTIN_TAGS[token] ?? true;
+            return () => value;
+        }
+        (negated ? negative : positive).add(token);
+        return tags => tags.has(token);
+    };
+
+    try {
+        const matches = parseOr(false);
+        if (i < tokens.length) return undefined;
+        return { matches, positive: [...positive].sort(), negative: [...negative].sort() };
+    } catch {
+        return undefined;
+    }
 }
 
+/**
+ * Smallest sets of custom tags a constraint builds with: `prod && linux` => [prod], `prod || staging` => [prod], [staging]
+ */
+function minimalGoTagSets(constraint: GoBuildConstraint): string[][] {
+    const candidates = constraint.positive.slice(0, 8);
+    const sets: string[][] = [];
+    for (let size = 0; size <= candidates.length; size++) {
+        for (let mask = 0; mask < 1 << candidates.length; mask++) {
+            const set = candidates.filter((_, bit) => mask & (1 << bit));
+            if (set.length !== size || sets.some(s => s.every(tag => set.includes(tag)))) continue;
+            if (constraint.matches(new Set(set))) sets.push(set);
+        }
+    }
+    return sets;
+}
+
+/**
+ * Compare dotted versions numerically (1.22.1 > 1.9); negative when a < b
+ * A pre-release sorts before its release (1.22rc1 < 1.22)
+ */
+export function compareVersions(a: string, b: string): number {
+    const [, releaseA, preA] = a.match(/^([\d.]*)(.*)$/)!;
+    const [, releaseB, preB] = b.match(/^([\d.]*)(.*)$/)!;
+    const pa = releaseA.split('.').map(Number);
+    const pb = releaseB.split('.').map(Number);
+    for (let i = 0; i < Math.max(pa.length, pb.length); i++) {
+        const diff = (pa[i] || 0) - (pb[i] || 0);
+        if (diff !== 0) return diff;
+    }
+    if (!preA || !preB) return preA ? -1 : preB ? 1 : 0;
+    return preA.localeCompare(preB, undefined, { numeric: true });
+}
+
+/**
+ * Built-in backend detectors, in the order they are registered (and run)
+ */
+const BUILTIN_BACKENDS: Array<DetectedBackend['language']> = [
+    'node', 'python', 'go', 'java', 'php', 'ruby', 'rust', 'dotnet', 'elixir', 'haskell'
+];
+
+/**
+ * Files each built-in detector starts from, listed by -vv traces (* matches any name)
+ */
+const DETECTOR_PROBES: Record<string, string[]> = {
+    frontend: ['package.json', 'index.html'],
+    node: ['package.json'],
+    python: ['requirements.txt', 'pyproject.toml', 'main.py'],
+    go: ['go.mod'],
+    java: ['pom.xml', 'build.gradle', 'build.gradle.kts', 'build.sbt'],
+    php: ['composer.json'],
+    ruby: ['Gemfile'],
+    rust: ['Cargo.toml'],
+    dotnet: ['*.csproj', '*.fsproj', '*.sln'],
+    elixir: ['mix.exs'],
+    haskell: ['*.cabal', 'stack.yaml', 'package.yaml']
+};
+
+/**
+ * Files detection looks for, custom detectors' markers included
+ */
+export function getStackSignals(): string[] {
+    return [...new Set([...Object.values(DETECTOR_PROBES).flat(), ...getDetectors().flatMap(d => d.markers || [])])];
+}
+
+/**
+ * Error message for a directory with no detectable stack: what was looked for and how to override it
+ */
+export function describeNoStack(dir: string): string {
+    return `No stack detected in ${dir} - looked for ${getStackSignals().join(', ')}. `
+        + `Run it on the directory that holds the project, or pass --stack <name> (${KNOWN_STACKS.join(', ')}) to generate for a stack anyway.`;
+}
+
+/**
+ * Framework and port used for a forced --stack when the directory has no detectable files yet
+ */
+const STACK_DEFAULTS: Record<Exclude<StackKey, 'frontend'>, { framework: string; port: number }> = {
+    node: { framework: 'node-express', port: 3000 },
+    python: { framework: 'python-fastapi', port: 8000 },
+    go: { framework: 'go-net-http', port: 8080 },
+    java: { framework: 'java-spring-boot', port: 8080 },
+    ruby: { framework: 'ruby-rails', port: 3000 },
+    php: { framework: 'php-laravel', port: 9000 },
+    dotnet: { framework: 'dotnet', port: 8080 },
+    rust: { framework: 'rust-actix', port: 8080 },
+    elixir: { framework: 'elixir-phoenix', port: 4000 }
+};
+
+/**
+ * Files that mark a directory as a project root during a recursive scan
+ */
+const PROJECT_MARKERS = [
+    'package.json', 'go.mod', 'requirements.txt', 'pyproject.toml', 'pom.xml',
+    'build.gradle', 'build.gradle.kts', 'Cargo.toml', 'Gemfile', 'composer.json', 'mix.exs'
+];
+
+/**
+ * Project marker extensions (.NET project files have per-project names)
+ */
+const PROJECT_MARKER_EXTENSIONS = ['.csproj', '.fsproj'];
+
+/**
+ * Directories never descended into during a recursive scan
+ */
+const SCAN_EXCLUDED_DIRS = [
+    'node_modules', 'vendor', '.git', 'dist', 'build', 'target', '.next', '.nuxt',
+    '.venv', 'venv', '__pycache__'
+];
+
+/**
+ * Environment variables that are set by the OS, toolchain or generator and
+ * never need to appear in .env.example
+ */
+const BUILTIN_ENV_VARS = [
+    'PATH', 'HOME', 'USER', 'PWD', 'SHELL', 'LANG', 'TERM', 'TMPDIR', 'TMP', 'TEMP', 'HOSTNAME',
+    'GOPATH', 'GOROOT', 'PYTHONPATH', 'NODE_ENV', 'PORT',
+    // Vite's import.meta.env built-ins
+    'MODE', 'DEV', 'PROD', 'SSR', 'BASE_URL'
+];
+
 /**
  * Enhanced Detection Engine - Main Class
  */
 export class EnhancedDetectionEngine {
     private basePath: string;
+    private options: DetectionOptions;
+
+    // Built-ins go through the same registry as custom detectors, at priority 0
+    static {
+        const engine = (context: DetectorContext) => new EnhancedDetectionEngine(context.root);
+        registerDetector({
+            name: 'frontend',
+            role: 'frontend',
+            detect: (dir, context) => engine(context).detectFrontend(dir, context.relativePath)
+        });
+        for (const language of BUILTIN_BACKENDS) {
+            registerDetector({
+                name: language,
+                role: 'backend',
+                detect: (dir, context) => engine(context).detectBackend(dir, context.relativePath, language)
+            });
+        }
+    }
 
-    constructor(basePath: string) {
+    constructor(basePath: string, options: DetectionOptions = {}) {
         this.basePath = basePath;
+        this.options = options;
     }
 
     /**
      * Perform deep scan for nested projects (up to depth 4)
      */
     private async performDeepScan(): Promise<EnhancedDetectionResult | null> {
-        console.log('[EnhancedDetectionEngine] Performing Deep Scan...');
+        log.info('Performing Deep Scan...');
 
         // Helper to recursively find config files
         const findConfigs = (dir: string, depth: number): string[] => {
@@ -128,11 +493,12 @@ export class EnhancedDetectionEngine {
 
         const bestDir = foundDirs[0];
         const relPath = path.relative(this.basePath, bestDir);
+        foundDirs.forEach(dir => deepScanLog.trace(`Candidate: ${path.relative(this.basePath, dir) || '.'}`));
+        deepScanLog.debug(`Picked the shallowest of ${foundDirs.length} candidate(s)`);
 
-        console.log(`[DeepScan] Found nested project at: ${relPath}`);
+        deepScanLog.info(`Found nested project at: ${relPath}`);
 
-        const frontend = await this.detectFrontend(bestDir, relPath);
-        const backend = await this.detectBackend(bestDir, relPath);
+        const { frontend, backend } = await this.detectService(bestDir, relPath);
 
         if (frontend.exists || backend.exists) {
             return {
@@ -154,16 +520,347 @@ export class EnhancedDetectionEngine {
      * Main detection method - detects everything
      */
     async detect(): Promise<EnhancedDetectionResult> {
-        console.log('[EnhancedDetectionEngine] Starting detection...');
+        log.info('Starting detection...');
+
+        let result: EnhancedDetectionResult;
+        if (this.options.stack) {
+            result = await this.detectForcedStack(this.options.stack);
+        } else if (this.options.recursive) {
+            result = await this.detectRecursiveProject();
+        } else {
+            // Check if monorepo first
+            const monorepoInfo = await this.detectMonorepo();
+            result = monorepoInfo.isMonorepo
+                ? await this.detectMonorepoProject(monorepoInfo)
+                : await this.detectSingleProject();
+        }
+
+        this.attachGoWorkspace(result);
+        this.attachEnvVars(result);
+        this.attachServiceWiring(result);
+
+        // Source/revision image labels; absent outside a git repository
+        result.git = readGitInfo(this.basePath);
+        if (result.git) {
+            log.info(`Git: ${result.git.sourceUrl || 'no remote'} @ ${result.git.revision?.slice(0, 12) || 'no commits'}`);
+        }
+        return result;
+    }
+
+    /**
+     * --stack: treat the target directory as the named stack
+     * Uses that stack's detector when its files are present, otherwise the stack's template defaults
+     */
+    private async detectForcedStack(stack: StackKey): Promise<EnhancedDetectionResult> {
+        if (!(KNOWN_STACKS as readonly string[]).includes(stack)) {
+            throw new Error(`Unknown stack "${stack}" (expected one of: ${KNOWN_STACKS.join(', ')})`);
+        }
+        log.info(`Stack forced to ${stack} - skipping auto-detection`);
 
-        // Check if monorepo first
-        const monorepoInfo = await this.detectMonorepo();
+        let frontend: DetectedFrontend | undefined;
+        let backend: DetectedBackend | undefined;
 
-        if (monorepoInfo.isMonorepo) {
-            return this.detectMonorepoProject(monorepoInfo);
+        if (stack === 'frontend') {
+            const detected = await this.detectFrontend(this.basePath, '.');
+            frontend = detected.exists ? detected : {
+                exists: true,
+                framework: 'html',
+                outputFolder: '.',
+                buildCommand: '',
+                packageManager: 'npm',
+                installCommand: '',
+                path: '.',
+                port: 80,
+                projectPath: this.basePath
+            };
         } else {
-            return this.detectSingleProject();
+            const detected = await this.detectBackend(this.basePath, '.', stack);
+            if (detected.exists) {
+                backend = detected;
+            } else {
+                log.info(`No ${stack} project files found - using ${stack} defaults`);
+                const { framework, port } = STACK_DEFAULTS[stack];
+                backend = {
+                    exists: true,
+                    framework,
+                    language: stack,
+                    path: '.',
+                    projectPath: this.basePath,
+                    port,
+                    ports: [port],
+                    entryPoint: stack === 'go' ? '.' : undefined,
+                    healthCheckPath: '/health'
+                };
+            }
+        }
+
+        return {
+            projectType: frontend ? 'frontend-only' : 'backend-only',
+            frontend,
+            backend,
+            databases: await this.detectDatabases(),
+            hasDockerfile: this.checkFileExists('Dockerfile'),
+            hasDockerCompose: this.checkFileExists('docker-compose.yml') || this.checkFileExists('docker-compose.yaml'),
+            hasNginxConfig: this.checkFileExists('nginx.conf'),
+            isMonorepo: false,
+            envFiles: this.detectEnvFiles(),
+            envVars: []
+        };
+    }
+
+    /**
+     * go.work at the root: member modules build from the workspace root so cross-module imports resolve
+     * RULE: Library modules (no main package) are dependencies of the others, not services
+     */
+    private attachGoWorkspace(result: EnhancedDetectionResult): void {
+        const workspace = this.readGoWork();
+        if (!workspace) return;
+
+        const isMember = (backend: DetectedBackend) => backend.language === 'go' && workspace.modules.includes(backend.path);
+        const isLibrary = (backend: DetectedBackend) =>
+            isMember(backend) && this.detectGoMainPackages(backend.projectPath || path.join(this.basePath, backend.path)).length === 0;
+
+        if (result.backend && isLibrary(result.backend)) {
+            result.backend = undefined;
+        }
+        if (result.monorepo) {
+            const libraries = result.monorepo.backends.filter(isLibrary).map(b => b.path);
+            if (libraries.length > 0) {
+                log.info(`go.work library modules (not services): ${libraries.join(', ')}`);
+                result.monorepo.backends = result.monorepo.backends.filter(b => !libraries.includes(b.path));
+            }
+        }
+
+        for (const backend of [...(result.backend ? [result.backend] : []), ...(result.monorepo?.backends || [])]) {
+            if (!isMember(backend)) continue;
+            backend.goWorkspace = workspace;
+            // Workspace mode needs a toolchain at least as new as go.work's go line
+            if (workspace.goVersion && compareVersions(workspace.goVersion, backend.languageVersion || '0') > 0) {
+                backend.languageVersion = workspace.goVersion;
+            }
+        }
+        log.info(`go.work: ${workspace.modules.length} module(s): ${workspace.modules.join(', ')}`);
+    }
+
+    /**
+     * Parse go.work at the project root (`use ./dir` and `use ( ... )` blocks)
+     */
+    private readGoWork(): GoWorkspace | undefined {
+        const goWorkPath = path.join(this.basePath, 'go.work');
+        if (!fs.existsSync(goWorkPath)) return undefined;
+
+        const content = fs.readFileSync(goWorkPath, 'utf-8').replace(/\/\/.*$/gm, '');
+        const dirs: string[] = [];
+        for (const block of content.matchAll(/^\s*use\s*\(([^)]*)\)/gm)) {
+            dirs.push(...block[1].split(/\s+/).filter(Boolean));
+        }
+        for (const single of content.matchAll(/^\s*use\s+([^\s(]+)\s*$/gm)) {
+            dirs.push(single[1]);
+        }
+
+        const modules = [...new Set(dirs
+            .map(dir => path.posix.normalize(dir.replace(/^"|"$/g, '')).replace(/\/$/, ''))
+            .filter(dir => !dir.startsWith('..') && fs.existsSync(path.join(this.basePath, dir, 'go.mod'))))]
+            .sort();
+        return modules.length > 0 ? { modules, goVersion: this.detectGoVersion(content) } : undefined;
+    }
+
+    /**
+     * Scan every detected service for environment variable references
+     */
+    private attachEnvVars(result: EnhancedDetectionResult): void {
+        const services: Array<DetectedFrontend | DetectedBackend> = [
+            ...(result.frontend ? [result.frontend] : []),
+            ...(result.backend ? [result.backend] : []),
+            ...(result.monorepo?.frontends || []),
+            ...(result.monorepo?.backends || [])
+        ];
+
+        const all = new Set<string>();
+        for (const service of services) {
+            service.envVars = this.detectEnvVars(service.projectPath || path.join(this.basePath, service.path));
+            service.envVars.forEach(v => all.add(v));
+        }
+        result.envVars = [...all].sort();
+    }
+
+    /**
+     * Record what compose needs to wire services together over container ports:
+     * frontend dev-server proxy targets and the EXPOSEd port of existing Dockerfiles
+     */
+    private attachServiceWiring(result: EnhancedDetectionResult): void {
+        const dirOf = (service: DetectedFrontend | DetectedBackend) => service.projectPath || path.join(this.basePath, service.path);
+
+        for (const frontend of [...(result.frontend ? [result.frontend] : []), ...(result.monorepo?.frontends || [])]) {
+            frontend.devProxy = this.detectDevProxy(dirOf(frontend));
+            frontend.dockerfilePort = this.detectDockerfilePort(dirOf(frontend));
+            frontend.hasDockerfile = fs.existsSync(path.join(dirOf(frontend), 'Dockerfile'));
+            frontend.hotReload = this.detectFrontendDevServer(dirOf(frontend), frontend.packageManager);
+            frontend.apiUrlEnvs = this.detectApiUrlEnvs(dirOf(frontend), frontend);
+            frontend.apiUrlEnvs.forEach(env => log.debug(`${frontend.path}: API URL from ${env.name} (${env.browser ? 'browser' : 'server-side'})${env.value ? ` = ${env.value} in ${env.file}` : ''}`));
+        }
+        for (const backend of [...(result.backend ? [result.backend] : []), ...(result.monorepo?.backends || [])]) {
+            backend.dockerfilePort = this.detectDockerfilePort(dirOf(backend));
+            backend.hasDockerfile = fs.existsSync(path.join(dirOf(backend), 'Dockerfile'));
+            backend.dockerfileCopiesGoWork = backend.hasDockerfile && backend.language === 'go'
+                && /^\s*COPY\s+(?:--\S+\s+)*go\.work\b/m.test(fs.readFileSync(path.join(dirOf(backend), 'Dockerfile'), 'utf-8'));
+            backend.hotReload = this.detectBackendWatcher(dirOf(backend), backend);
+        }
+    }
+
+    /**
+     * Detect the dev server a frontend's package.json scripts run
+     * RULE: Only a script the project already has - no dev tooling is added for it
+     */
+    private detectFrontendDevServer(dir: string, packageManager: DetectedFrontend['packageManager']): HotReload | undefined {
+        const scripts = this.readPackageScripts(dir);
+        for (const script of ['dev', 'start', 'serve']) {
+            const command = scripts[script];
+            const server = command && FRONTEND_DEV_SERVERS.find(s => s.pattern.test(command));
+            if (!server) continue;
+
+            const port = command.match(/(?:-p|--port)[\s=](\d+)/);
+            // Dev servers that bind localhost need --host to be reachable from outside the container
+            const hostArgs = server.needsHost ? [...(packageManager === 'npm' ? ['--'] : []), '--host', '0.0.0.0'] : [];
+            return {
+                tool: server.tool,
+                command: [packageManager, 'run', script, ...hostArgs],
+                port: port ? parseInt(port[1], 10) : server.port
+            };
+        }
+        return undefined;
+    }
+
+    /**
+     * Detect the file watcher a backend already depends on (air for Go, nodemon & co. for Node)
+     */
+    private detectBackendWatcher(dir: string, backend: DetectedBackend): HotReload | undefined {
+        if (backend.language === 'go') {
+            const goModPath = path.join(dir, 'go.mod');
+            const goMod = fs.existsSync(goModPath) ? fs.readFileSync(goModPath, 'utf-8') : '';
+            const airModule = goMod.match(/\b(github\.com\/(?:air-verse|cosmtrek)\/air)\b/)?.[1];
+            const hasConfig = fs.existsSync(path.join(dir, '.air.toml'));
+            if (!airModule && !hasConfig) return undefined;
+
+            // Go 1.24 tool directive > module requirement > .air.toml alone (fetched on first start)
+            const command = airModule && new RegExp(`^\\s*(tool\\s+)?${airModule.replace(/\./g, '\\.')}\\s*$`, 'm').test(goMod)
+                ? ['go', 'tool', 'air']
+                : ['go', 'run', airModule || 'github.com/air-verse/air@latest'];
+            // Without .air.toml air builds the module root; point it at the detected main package
+            if (!hasConfig && backend.entryPoint && backend.entryPoint !== '.') {
+                command.push('--build.cmd', `go build -o ./tmp/main ${backend.entryPoint}`, '--build.bin', './tmp/main');
+            }
+            return { tool: 'air', command };
+        }
+
+        if (backend.language === 'node') {
+            const scripts = this.readPackageScripts(dir);
+            for (const script of ['dev', 'start:dev', 'watch']) {
+                const watcher = scripts[script]?.match(/\b(nodemon|ts-node-dev|tsx watch|node --watch|nest start --watch)\b/);
+                if (watcher) {
+                    return { tool: watcher[1], command: [backend.packageManager || 'npm', 'run', script] };
+                }
+            }
+        }
+
+        return undefined;
+    }
+
+    private readPackageScripts(dir: string): Record<string, string> {
+        const packageJsonPath = path.join(dir, 'package.json');
+        if (!fs.existsSync(packageJsonPath)) return {};
+        try {
+            return JSON.parse(fs.readFileSync(packageJsonPath, 'utf-8')).scripts || {};
+        } catch {
+            return {};
+        }
+    }
+
+    /**
+     * Detect the dev-server proxy a frontend uses to reach its API
+     */
+    private detectDevProxy(dir: string): { path?: string; target: string } | undefined {
+        // CRA: "proxy": "http://localhost:5000" forwards every unknown request
+        const packageJsonPath = path.join(dir, 'package.json');
+        if (fs.existsSync(packageJsonPath)) {
+            try {
+                const proxy = JSON.parse(fs.readFileSync(packageJsonPath, 'utf-8')).proxy;
+                if (typeof proxy === 'string') return { target: proxy };
+            } catch {
+                // Ignore JSON parse errors
+            }
+        }
+
+        // Vite: server: { proxy: { '/api': 'http://localhost:5000' } } or { '/api': { target: '...' } }
+        for (const file of ['vite.config.ts', 'vite.config.js', 'vite.config.mts', 'vite.config.mjs']) {
+            const configPath = path.join(dir, file);
+            if (!fs.existsSync(configPath)) continue;
+            const match = fs.readFileSync(configPath, 'utf-8')
+                .match(/proxy\s*:\s*\{\s*['"]?(\/[\w\-\/]*)['"]?\s*:\s*(?:['"`]([^'"`]+)['"`]|\{[^}]*?target\s*:\s*['"`]([^'"`]+)['"`])/);
+            if (match) return { path: match[1], target: match[2] || match[3] };
+        }
+
+        // Vue CLI: devServer: { proxy: 'http://localhost:5000' }
+        const vueConfigPath = path.join(dir, 'vue.config.js');
+        if (fs.existsSync(vueConfigPath)) {
+            const match = fs.readFileSync(vueConfigPath, 'utf-8').match(/devServer[\s\S]*?proxy\s*:\s*['"`]([^'"`]+)['"`]/);
+            if (match) return { target: match[1] };
+        }
+
+        return undefined;
+    }
+
+    /**
+     * API base URL variables a frontend reads, from its source and its .env files
+     * RULE: A variable is browser-side only with the prefix its framework inlines into client code
+     */
+    private detectApiUrlEnvs(dir: string, frontend: DetectedFrontend): ApiUrlEnv[] {
+        const values = new Map<string, { value: string; file: string }>();
+        for (const file of FRONTEND_ENV_FILES) {
+            const envPath = path.join(dir, file);
+            if (!fs.existsSync(envPath)) continue;
+            for (const line of fs.readFileSync(envPath, 'utf-8').split('\n')) {
+                const match = line.match(/^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*?)\s*$/);
+                if (!match || values.has(match[1])) continue;
+                const value = match[2].replace(/^(['"])(.*)\1$/, '$2');
+                if (value) values.set(match[1], { value, file });
+            }
         }
+
+        const prefixes = this.getBrowserEnvPrefixes(frontend);
+        const names = [...new Set([...(frontend.envVars || []), ...values.keys()])].filter(name => API_URL_ENV.test(name));
+        return names
+            .map(name => ({ name, browser: prefixes.some(p => name.startsWith(p)), ...values.get(name) }))
+            .sort((a, b) => Number(b.browser) - Number(a.browser) || a.name.localeCompare(b.name));
+    }
+
+    /**
+     * Prefixes a frontend framework exposes to browser code (everything else stays server-side or is dropped)
+     */
+    private getBrowserEnvPrefixes(frontend: DetectedFrontend): string[] {
+        if (frontend.framework === 'nextjs') return ['NEXT_PUBLIC_'];
+        if (frontend.framework === 'nuxt') return ['NUXT_PUBLIC_'];
+        if (frontend.framework === 'gatsby') return ['GATSBY_'];
+        if (frontend.variant === 'cra') return ['REACT_APP_'];
+        if (frontend.framework === 'sveltekit' || frontend.variant === 'kit' || frontend.framework === 'astro') return ['PUBLIC_', 'VITE_'];
+        if (frontend.framework === 'vue') return ['VITE_', 'VUE_APP_'];
+        return ['VITE_', 'REACT_APP_', 'VUE_APP_', 'PUBLIC_'];
+    }
+
+    /**
+     * Port EXPOSEd by a Dockerfile already in dir (literal, or the default of an ARG used in EXPOSE)
+     */
+    private detectDockerfilePort(dir: string): number | undefined {
+        const dockerfilePath = path.join(dir, 'Dockerfile');
+        if (!fs.existsSync(dockerfilePath)) return undefined;
+
+        const content = fs.readFileSync(dockerfilePath, 'utf-8');
+        const expose = content.match(/^\s*EXPOSE\s+(?:(\d+)|\$\{?(\w+)\}?)/mi);
+        if (!expose) return undefined;
+        if (expose[1]) return parseInt(expose[1], 10);
+
+        const arg = content.match(new RegExp(`^\\s*(?:ARG|ENV)\\s+${expose[2]}=(\\d+)`, 'mi'));
+        return arg ? parseInt(arg[1], 10) : undefined;
     }
 
     /**
@@ -180,8 +877,14 @@ export class EnhancedDetectionEngine {
         const hasNx = fs.existsSync(path.join(this.basePath, 'nx.json'));
         const hasTurbo = fs.existsSync(path.join(this.basePath, 'turbo.json'));
         const hasRush = fs.existsSync(path.join(this.basePath, 'rush.json'));
+        const goWork = this.readGoWork();
 
-        const isMonorepo = hasYarnWorkspaces || hasPnpmWorkspaces || hasLerna || hasNx || hasTurbo || hasRush;
+        const isMonorepo = hasYarnWorkspaces || hasPnpmWorkspaces || hasLerna || hasNx || hasTurbo || hasRush || !!goWork;
+        const indicators = ([
+            [hasYarnWorkspaces, 'package.json workspaces'], [hasPnpmWorkspaces, 'pnpm-workspace.yaml'], [hasLerna, 'lerna.json'],
+            [hasNx, 'nx.json'], [hasTurbo, 'turbo.json'], [hasRush, 'rush.json'], [!!goWork, 'go.work']
+        ] as Array<[boolean, string]>).filter(([present]) => present).map(([, name]) => name);
+        log.debug(`Monorepo indicators: ${indicators.length > 0 ? indicators.join(', ') : 'none'}`);
 
         if (!isMonorepo) {
             // Also check for common monorepo folder structures
@@ -189,6 +892,7 @@ export class EnhancedDetectionEngine {
             const hasPackagesFolder = fs.existsSync(path.join(this.basePath, 'packages'));
 
             if (hasAppsFolder || hasPackagesFolder) {
+                log.debug(`Monorepo by layout: ${[hasAppsFolder && 'apps/', hasPackagesFolder && 'packages/'].filter(Boolean).join(' and ')} present`);
                 // Get workspaces from folders
                 const workspaces = await this.getWorkspaces('yarn');
                 return {
@@ -245,6 +949,7 @@ export class EnhancedDetectionEngine {
                     }
                 }
 
+                log.debug(`Fullstack folders: ${spaces.join(', ')} - ${hasProject ? 'project files found, scanning them as services' : 'no project files inside, ignored'}`);
                 if (hasProject) {
                     // Try to get workspaces from package.json first, fallback to detected spaces
                     const workspacesFromPackageJson = await this.getWorkspaces('yarn');
@@ -272,9 +977,10 @@ export class EnhancedDetectionEngine {
         if (hasNx) tool = 'nx';
         if (hasTurbo) tool = 'turbo';
         if (hasRush) tool = 'rush';
+        if (goWork && !(hasYarnWorkspaces || hasPnpmWorkspaces || hasLerna || hasNx || hasTurbo || hasRush)) tool = 'go-work';
 
-        // Get workspaces
-        const workspaces = await this.getWorkspaces(tool);
+        // Get workspaces (go.work modules first, then the JS/common-folder workspaces)
+        const workspaces = [...new Set([...(goWork?.modules || []), ...await this.getWorkspaces(tool)])];
 
         return {
             isMonorepo: true,
@@ -353,7 +1059,7 @@ export class EnhancedDetectionEngine {
                     }
                 }
             } catch (e) {
-                console.warn('[EnhancedDetectionEngine] Failed to parse package.json for workspaces');
+                log.warn('Failed to parse package.json for workspaces');
             }
         }
         
@@ -397,37 +1103,47 @@ export class EnhancedDetectionEngine {
      * Detect monorepo project structure
      */
     private async detectMonorepoProject(monorepoInfo: MonorepoInfo): Promise<EnhancedDetectionResult> {
-        console.log('[EnhancedDetectionEngine] Detecting monorepo structure...');
+        log.info('Detecting monorepo structure...');
 
         const frontends: DetectedFrontend[] = [];
         const backends: DetectedBackend[] = [];
+        const errors: Array<{ path: string; message: string }> = [];
 
-        // Scan each workspace
-        for (const workspace of monorepoInfo.workspaces || []) {
+        // Scan workspaces in parallel; results keep workspace order
+        const scanned = await mapWithConcurrency(monorepoInfo.workspaces || [], this.getConcurrency(), async workspace => {
             const workspacePath = path.join(this.basePath, workspace);
-
-            // Detect frontend
-            const frontend = await this.detectFrontend(workspacePath, workspace);
-            if (frontend.exists) {
-                frontends.push(frontend);
+            return this.detectService(workspacePath, workspace);
+        });
+
+        const unrecognized: string[] = [];
+        for (const { item, value, error } of scanned) {
+            if (error) {
+                errors.push(this.recordDetectionError(item, error));
+                continue;
             }
-
-            // Detect backend
-            const backend = await this.detectBackend(workspacePath, workspace);
-            if (backend.exists) {
-                backends.push(backend);
+            if (value!.frontend.exists) frontends.push(value!.frontend);
+            if (value!.backend.exists) backends.push(value!.backend);
+            if (!value!.frontend.exists && !value!.backend.exists) {
+                log.info(`${item}: no stack detected - skipped`);
+                unrecognized.push(item);
             }
         }
 
         monorepoInfo.frontends = frontends;
         monorepoInfo.backends = backends;
+        if (unrecognized.length > 0) {
+            monorepoInfo.unrecognized = unrecognized;
+        }
+        if (errors.length > 0) {
+            monorepoInfo.errors = errors;
+        }
 
         // Detect databases
-        const databases = await this.detectDatabases();
+        const databases = await this.detectDatabases(backends.map(b => b.path));
         const envFiles = this.detectEnvFiles();
 
         return {
-            projectType: 'monorepo',
+            projectType: this.hasServices(frontends, backends, errors) ? 'monorepo' : 'none',
             monorepo: monorepoInfo,
             databases,
             hasDockerfile: this.checkFileExists('Dockerfile'),
@@ -440,53 +1156,272 @@ export class EnhancedDetectionEngine {
     }
 
     /**
-     * Detect single project (non-monorepo)
+     * Recursive scan: every directory that looks like a project root becomes a service
+     * RULE: node_modules/vendor are never descended into
      */
-    private async detectSingleProject(): Promise<EnhancedDetectionResult> {
-        console.log('[EnhancedDetectionEngine] Detecting single project...');
-
-        const frontend = await this.detectFrontend(this.basePath, '.');
-        const backend = await this.detectBackend(this.basePath, '.');
-        const databases = await this.detectDatabases();
-        const envFiles = this.detectEnvFiles();
+    private async detectRecursiveProject(): Promise<EnhancedDetectionResult> {
+        log.info('Recursively scanning for project roots...');
 
-        // Determine project type
-        let projectType: ProjectType;
-        if (frontend.exists && backend.exists) {
-            projectType = 'fullstack';
-        } else if (frontend.exists) {
-            projectType = 'frontend-only';
-        } else if (backend.exists) {
-            projectType = 'backend-only';
-        } else {
-            // Attempt Deep Scan if nothing found at root
-            const deepResult = await this.performDeepScan();
-            if (deepResult) {
-                return deepResult;
+        const frontends: DetectedFrontend[] = [];
+        const backends: DetectedBackend[] = [];
+        const unrecognized: string[] = [];
+        const errors: Array<{ path: string; message: string }> = [];
+
+        // Per-root detection runs on a bounded pool; one failing service never aborts the scan
+        const roots = (await this.findProjectRoots(this.basePath))
+            .map(root => path.relative(this.basePath, root).split(path.sep).join('/') || '.');
+        const scanned = await mapWithConcurrency(roots, this.getConcurrency(), async relativePath => {
+            const projectRoot = path.join(this.basePath, relativePath);
+            return this.detectService(projectRoot, relativePath);
+        });
+
+        for (const { item: relativePath, value, error } of scanned) {
+            if (error) {
+                errors.push(this.recordDetectionError(relativePath, error));
+                continue;
             }
-            projectType = 'backend-only'; // Default fallback
+            const { frontend, backend } = value!;
+            if (frontend.exists) frontends.push(frontend);
+            if (backend.exists) backends.push(backend);
+            if (!frontend.exists && !backend.exists) unrecognized.push(relativePath);
         }
 
+        log.info(`Recursive scan found ${frontends.length} frontend(s), ${backends.length} backend(s)`);
+
         return {
-            projectType,
-            frontend: frontend.exists ? frontend : undefined,
-            backend: backend.exists ? backend : undefined,
-            databases,
+            projectType: this.hasServices(frontends, backends, errors) ? 'monorepo' : 'none',
+            monorepo: {
+                isMonorepo: true,
+                workspaces: [...frontends, ...backends].map(s => s.path),
+                frontends,
+                backends,
+                unrecognized,
+                errors: errors.length > 0 ? errors : undefined
+            },
+            databases: await this.detectDatabases(backends.map(b => b.path)),
             hasDockerfile: this.checkFileExists('Dockerfile'),
             hasDockerCompose: this.checkFileExists('docker-compose.yml') || this.checkFileExists('docker-compose.yaml'),
             hasNginxConfig: this.checkFileExists('nginx.conf'),
-            isMonorepo: false,
-            envFiles: envFiles,
+            isMonorepo: true,
+            envFiles: this.detectEnvFiles(),
             envVars: []
         };
     }
 
     /**
-     * Detect frontend framework
+     * Find every directory containing a project marker file
+     * Walks one tree level at a time with concurrent readdirs; returned in sorted tree order (parents first)
      */
-    private async detectFrontend(basePath: string, relativePath: string): Promise<DetectedFrontend> {
-        const packageJsonPath = path.join(basePath, 'package.json');
-
+    private async findProjectRoots(baseDir: string): Promise<string[]> {
+        const roots: string[] = [];
+        const markers = new Set([...PROJECT_MARKERS, ...getDetectors().flatMap(d => d.markers || [])]);
+        let level = [baseDir];
+
+        for (let depth = 0; depth <= 8 && level.length > 0; depth++) {
+            const listed = await mapWithConcurrency(level, this.getConcurrency(),
+                dir => fs.promises.readdir(dir, { withFileTypes: true }));
+
+            const nextLevel: string[] = [];
+            for (const { item: dir, value: entries } of listed) {
+                if (!entries) continue; // unreadable directory
+                if (entries.some(e => e.isFile() && (markers.has(e.name) || PROJECT_MARKER_EXTENSIONS.includes(path.extname(e.name))))) {
+                    roots.push(dir);
+                }
+                nextLevel.push(...entries
+                    .filter(e => e.isDirectory() && !SCAN_EXCLUDED_DIRS.includes(e.name) && !e.name.startsWith('.'))
+                    .map(e => path.join(dir, e.name)));
+            }
+            level = nextLevel;
+        }
+
+        // Segment-wise sort == depth-first order with sorted children
+        const segments = (p: string) => path.relative(baseDir, p).split(path.sep).filter(Boolean);
+        return roots.sort((a, b) => {
+            const sa = segments(a);
+            const sb = segments(b);
+            for (let i = 0; i < Math.min(sa.length, sb.length); i++) {
+                if (sa[i] !== sb[i]) return sa[i] < sb[i] ? -1 : 1;
+            }
+            return sa.length - sb.length;
+        });
+    }
+
+    /**
+     * Whether a multi-service scan found anything to generate for
+     * RULE: Services that failed detection count - their errors are reported instead of "no stack"
+     */
+    private hasServices(frontends: DetectedFrontend[], backends: DetectedBackend[], errors: unknown[]): boolean {
+        return frontends.length + backends.length + errors.length > 0;
+    }
+
+    /**
+     * Worker pool size for per-service scans
+     */
+    private getConcurrency(): number {
+        return this.options.concurrency && this.options.concurrency > 0 ? this.options.concurrency : defaultConcurrency();
+    }
+
+    /**
+     * Log a per-service detection failure and keep going
+     */
+    private recordDetectionError(servicePath: string, error: Error): { path: string; message: string } {
+        log.info(`Detection failed for ${servicePath}: ${error.message}`);
+        return { path: servicePath, message: error.message };
+    }
+
+    /**
+     * Detect single project (non-monorepo)
+     */
+    private async detectSingleProject(): Promise<EnhancedDetectionResult> {
+        log.info('Detecting single project...');
+
+        const { frontend, backend } = await this.detectService(this.basePath, '.');
+        const databases = await this.detectDatabases();
+        const envFiles = this.detectEnvFiles();
+
+        // Determine project type
+        let projectType: ProjectType;
+        if (frontend.exists && backend.exists) {
+            projectType = 'fullstack';
+        } else if (frontend.exists) {
+            projectType = 'frontend-only';
+        } else if (backend.exists) {
+            projectType = 'backend-only';
+        } else {
+            // Attempt Deep Scan if nothing found at root
+            const deepResult = await this.performDeepScan();
+            if (deepResult) {
+                return deepResult;
+            }
+            log.info('No stack detected');
+            projectType = 'none';
+        }
+
+        return {
+            projectType,
+            frontend: frontend.exists ? frontend : undefined,
+            backend: backend.exists ? backend : undefined,
+            databases,
+            hasDockerfile: this.checkFileExists('Dockerfile'),
+            hasDockerCompose: this.checkFileExists('docker-compose.yml') || this.checkFileExists('docker-compose.yaml'),
+            hasNginxConfig: this.checkFileExists('nginx.conf'),
+            isMonorepo: false,
+            envFiles: envFiles,
+            envVars: []
+        };
+    }
+
+    /**
+     * Run the registered detectors (built-in and custom) on one directory
+     * RULE: Highest priority first; the first frontend and the first backend match win
+     * RULE: A Node server and a frontend build in one package.json are one backend that serves its own build
+     */
+    private async detectService(dir: string, relativePath: string): Promise<{ frontend: DetectedFrontend; backend: DetectedBackend }> {
+        const context: DetectorContext = { root: this.basePath, relativePath };
+        const found: { frontend?: DetectedFrontend; backend?: DetectedBackend } = {};
+        // -v keeps running detectors after a role is filled so the losing matches can be reported
+        const explain = log.enabled('debug');
+        const outranked: Record<Detector['role'], string[]> = { frontend: [], backend: [] };
+        const winningPriority: Partial<Record<Detector['role'], number>> = {};
+        const detectors = getDetectors();
+
+        for (const [rank, detector] of detectors.entries()) {
+            if (found[detector.role] && !explain) continue;
+            if (log.enabled('trace')) {
+                log.trace(`${relativePath}: ${detector.name} (${detector.role}, priority ${detector.priority || 0}, #${rank + 1} of ${detectors.length}) ${this.describeProbes(dir, detector)}`);
+            }
+            let service: DetectedService | undefined;
+            try {
+                service = await detector.detect(dir, context);
+            } catch (error) {
+                // Only the detector that would have won may fail the service
+                if (!found[detector.role]) throw error;
+                log.trace(`${relativePath}: ${detector.name} - failed (ignored, ${detector.role} already matched): ${error instanceof Error ? error.message : error}`);
+                continue;
+            }
+            if (!service || !service.exists) {
+                log.trace(`${relativePath}: ${detector.name} - no match`);
+                continue;
+            }
+            log.trace(`${relativePath}: ${detector.name} - matched ${service.framework}`);
+            if (found[detector.role]) {
+                const priority = detector.priority || 0;
+                const why = priority < winningPriority[detector.role]! ? `priority ${priority}` : 'same priority, registered later';
+                outranked[detector.role].push(`${detector.name} (${service.framework}; ${why})`);
+                continue;
+            }
+            winningPriority[detector.role] = detector.priority || 0;
+
+            // Custom detectors may leave out the bookkeeping fields
+            const detected = { ...service, path: service.path || relativePath, projectPath: service.projectPath || dir, detector: detector.name };
+            if (detector.role === 'frontend') {
+                found.frontend = detected as DetectedFrontend;
+            } else {
+                found.backend = detected as DetectedBackend;
+            }
+            if (found.frontend && found.backend && !explain) break;
+        }
+
+        for (const role of ['frontend', 'backend'] as const) {
+            const winner = found[role];
+            if (!winner) {
+                log.debug(`${relativePath}: no ${role} detector matched`);
+                continue;
+            }
+            const lost = outranked[role].length > 0 ? ` - first match wins over ${outranked[role].join(', ')}` : '';
+            log.debug(`${relativePath}: ${role} detected by ${winner.detector} (${winner.framework}, priority ${winningPriority[role]})${lost}`);
+        }
+
+        if (found.frontend && found.backend?.language === 'node' && found.frontend.framework !== 'html') {
+            log.info(`${relativePath}: ${found.frontend.framework} build served by the ${found.backend.framework} server - treating it as a backend`);
+            found.backend.servesFrontend = found.frontend.framework;
+            found.frontend = undefined;
+        }
+
+        return {
+            frontend: found.frontend || {
+                exists: false,
+                framework: 'unknown',
+                outputFolder: 'dist',
+                buildCommand: 'npm run build',
+                packageManager: 'npm',
+                installCommand: 'npm ci',
+                path: relativePath,
+                projectPath: dir
+            },
+            backend: found.backend || {
+                exists: false,
+                framework: 'unknown',
+                language: 'node',
+                path: relativePath,
+                projectPath: dir
+            }
+        };
+    }
+
+    /**
+     * "looked for a, b - found a" for -vv traces; custom detectors list their markers, if any
+     */
+    private describeProbes(dir: string, detector: Detector): string {
+        const probes = DETECTOR_PROBES[detector.name] || detector.markers || [];
+        if (probes.length === 0) return '- no probe files declared';
+
+        let entries: string[] = [];
+        try {
+            entries = fs.readdirSync(dir);
+        } catch { /* unreadable directory */ }
+        const present = probes.filter(probe => probe.startsWith('*')
+            ? entries.some(entry => entry.endsWith(probe.slice(1)))
+            : entries.includes(probe));
+        return `looked for ${probes.join(', ')} - found ${present.length > 0 ? present.join(', ') : 'none'}`;
+    }
+
+    /**
+     * Detect frontend framework
+     */
+    private async detectFrontend(basePath: string, relativePath: string): Promise<DetectedFrontend> {
+        const packageJsonPath = path.join(basePath, 'package.json');
+
         if (!fs.existsSync(packageJsonPath)) {
             // Check for Static HTML sites (index.html present, no package.json)
             if (fs.existsSync(path.join(basePath, 'index.html'))) {
@@ -519,7 +1454,7 @@ export class EnhancedDetectionEngine {
         try {
             packageJson = JSON.parse(fs.readFileSync(packageJsonPath, 'utf-8'));
         } catch (e) {
-            console.warn(`[EnhancedDetectionEngine] Failed to parse package.json at ${packageJsonPath}`);
+            log.warn(`Failed to parse package.json at ${packageJsonPath}`);
             return {
                 exists: false,
                 framework: 'unknown',
@@ -563,8 +1498,8 @@ export class EnhancedDetectionEngine {
         }
 
         // Detect config files
-        const hasViteConfig = fs.existsSync(path.join(basePath, 'vite.config.js')) ||
-            fs.existsSync(path.join(basePath, 'vite.config.ts'));
+        const hasViteConfig = ['vite.config.js', 'vite.config.ts', 'vite.config.mjs', 'vite.config.mts']
+            .some(f => fs.existsSync(path.join(basePath, f)));
         const hasCRAConfig = !!dependencies['react-scripts'];
         const hasNextConfig = fs.existsSync(path.join(basePath, 'next.config.js')) ||
             fs.existsSync(path.join(basePath, 'next.config.mjs'));
@@ -587,7 +1522,7 @@ export class EnhancedDetectionEngine {
                     nextConfigContent.output = 'export';
                 }
             } catch (err) {
-                console.log('[EnhancedDetectionEngine] Could not read next.config.js');
+                log.debug('Could not read next.config.js');
             }
         }
 
@@ -622,17 +1557,21 @@ export class EnhancedDetectionEngine {
             packageManager: outputInfo.packageManager,
             installCommand: outputInfo.installCommand,
             path: relativePath,
-            port: 3000
+            port: 3000,
+            privateDependencies: this.detectPrivateNpmRegistry(basePath)
         };
     }
 
     /**
      * Detect backend framework
      */
-    private async detectBackend(basePath: string, relativePath: string): Promise<DetectedBackend> {
+    private async detectBackend(basePath: string, relativePath: string, only?: DetectedBackend['language']): Promise<DetectedBackend> {
+        // --stack restricts detection to one language so mixed directories resolve to it
+        const wants = (language: DetectedBackend['language']) => !only || only === language;
+
         // Check for Node.js backend
         const packageJsonPath = path.join(basePath, 'package.json');
-        if (fs.existsSync(packageJsonPath)) {
+        if (wants('node') && fs.existsSync(packageJsonPath)) {
             let packageJson: any;
             try {
                 packageJson = JSON.parse(fs.readFileSync(packageJsonPath, 'utf-8'));
@@ -646,14 +1585,28 @@ export class EnhancedDetectionEngine {
             }
             const dependencies = { ...packageJson.dependencies, ...packageJson.devDependencies };
 
-            // Check if it's a backend (not frontend)
-            const isBackend = !!(
+            // Try to detect start script and entry point
+            const startScript = packageJson.scripts?.start || 'node server.js';
+            const mainFile = packageJson.main || 'index.js';
+
+            // Detect actual entry point from start script
+            let entryPoint = 'server.js';
+            if (startScript.includes('node ')) {
+                const match = startScript.match(/node\s+([^\s]+)/);
+                if (match) entryPoint = match[1];
+            } else if (mainFile) {
+                entryPoint = mainFile;
+            }
+
+            // Check if it's a backend (not frontend): a server framework, or an entry that creates and starts an HTTP server
+            const hasServerFramework = !!(
                 dependencies['express'] ||
                 dependencies['koa'] ||
                 dependencies['fastify'] ||
                 dependencies['@nestjs/core'] ||
                 dependencies['hapi']
             );
+            const isBackend = hasServerFramework || this.startsHttpServer(path.join(basePath, entryPoint));
 
             if (isBackend) {
                 let framework = 'node-express';
@@ -661,32 +1614,24 @@ export class EnhancedDetectionEngine {
                 else if (dependencies['koa']) framework = 'node-koa';
                 else if (dependencies['fastify']) framework = 'node-fastify';
                 else if (dependencies['hapi']) framework = 'node-hapi';
+                else if (!dependencies['express']) framework = 'node-http';
 
                 const packageManager = this.detectPackageManager(basePath);
 
-                // Try to detect start script and entry point
-                const startScript = packageJson.scripts?.start || 'node server.js';
-                const mainFile = packageJson.main || 'index.js';
-
-                // Detect actual entry point from start script
-                let entryPoint = 'server.js';
-                if (startScript.includes('node ')) {
-                    const match = startScript.match(/node\s+([^\s]+)/);
-                    if (match) entryPoint = match[1];
-                } else if (mainFile) {
-                    entryPoint = mainFile;
-                }
-
                 return {
                     exists: true,
                     framework,
                     language: 'node',
                     packageManager,
+                    lockFile: this.getNodeLockFile(basePath, packageManager),
                     path: relativePath,
                     projectPath: basePath,
-                    port: 8000,
+                    port: this.detectNodeListenPort(basePath, entryPoint) || 8000,
                     dependencies: { ...packageJson.dependencies, ...packageJson.devDependencies },
-                    entryPoint
+                    entryPoint,
+                    healthCheckPath: '/health',
+                    hasBuildScript: !!packageJson.scripts?.build,
+                    privateDependencies: this.detectPrivateNpmRegistry(basePath)
                 };
             }
         }
@@ -694,24 +1639,29 @@ export class EnhancedDetectionEngine {
         // Check for Python backend
         const requirementsPath = path.join(basePath, 'requirements.txt');
         const pyprojectPath = path.join(basePath, 'pyproject.toml');
+        const mainPyPath = path.join(basePath, 'main.py');
+        const mainPy = fs.existsSync(mainPyPath) ? fs.readFileSync(mainPyPath, 'utf-8') : '';
+        const hasFastApiMain = /FastAPI\s*\(/.test(mainPy);
 
-        if (fs.existsSync(requirementsPath) || fs.existsSync(pyprojectPath)) {
+        if (wants('python') && (fs.existsSync(requirementsPath) || fs.existsSync(pyprojectPath) || hasFastApiMain)) {
             let framework = 'python-flask';
             let packageManager = 'pip';
             let entryPoint = 'app.py';
 
-            if (fs.existsSync(requirementsPath)) {
-                const requirements = fs.readFileSync(requirementsPath, 'utf-8');
-                if (requirements.includes('fastapi')) {
-                    framework = 'python-fastapi';
-                    entryPoint = 'main.py';
-                } else if (requirements.includes('django')) {
-                    framework = 'python-django';
-                    entryPoint = 'manage.py';
-                } else if (requirements.includes('flask')) {
-                    framework = 'python-flask';
-                    entryPoint = 'app.py';
-                }
+            // Dependency declarations (requirements.txt takes precedence over pyproject.toml)
+            const requirements = fs.existsSync(requirementsPath) ? fs.readFileSync(requirementsPath, 'utf-8').toLowerCase() : '';
+            const pyproject = fs.existsSync(pyprojectPath) ? fs.readFileSync(pyprojectPath, 'utf-8') : '';
+            const declared = requirements || pyproject.toLowerCase();
+
+            if (declared.includes('fastapi') || hasFastApiMain) {
+                framework = 'python-fastapi';
+                entryPoint = 'main.py';
+            } else if (declared.includes('django')) {
+                framework = 'python-django';
+                entryPoint = 'manage.py';
+            } else if (declared.includes('flask')) {
+                framework = 'python-flask';
+                entryPoint = 'app.py';
             }
 
             // Check for actual Python files
@@ -723,8 +1673,7 @@ export class EnhancedDetectionEngine {
                 }
             }
 
-            if (fs.existsSync(pyprojectPath)) {
-                const pyproject = fs.readFileSync(pyprojectPath, 'utf-8');
+            if (pyproject) {
                 if (pyproject.includes('poetry')) packageManager = 'poetry';
                 else if (pyproject.includes('pipenv')) packageManager = 'pipenv';
             }
@@ -736,14 +1685,18 @@ export class EnhancedDetectionEngine {
                 packageManager,
                 path: relativePath,
                 projectPath: basePath,
-                port: 8000,
-                entryPoint
+                port: this.detectUvicornPort(basePath) || 8000,
+                entryPoint,
+                languageVersion: this.detectPythonVersion(basePath, pyproject),
+                dependencyFile: fs.existsSync(requirementsPath) ? 'requirements.txt' : (pyproject ? 'pyproject.toml' : undefined),
+                healthCheckPath: '/health',
+                asgiApp: framework === 'python-fastapi' ? this.detectAsgiApp(basePath, entryPoint) : undefined
             };
         }
 
         // Check for Go backend
         const goModPath = path.join(basePath, 'go.mod');
-        if (fs.existsSync(goModPath)) {
+        if (wants('go') && fs.existsSync(goModPath)) {
             const goMod = fs.readFileSync(goModPath, 'utf-8');
             let framework = 'go-gin';
 
@@ -751,13 +1704,33 @@ export class EnhancedDetectionEngine {
             else if (goMod.includes('github.com/gofiber/fiber')) framework = 'go-fiber';
             else if (goMod.includes('github.com/labstack/echo')) framework = 'go-echo';
 
+            // Scan Go source for listen addresses; 8080 is the documented fallback
+            const ports = this.detectGoPorts(basePath);
+
+            // Build target is the main package (., ./cmd/server, ...), not necessarily the module root
+            const mainPackages = this.detectGoMainPackages(basePath);
+
+            // Registered routes pick the HEALTHCHECK endpoint (Gin and Echo share the r.GET(...) style)
+            const routes = framework === 'go-gin' || framework === 'go-echo' ? this.detectGoRoutes(basePath) : [];
+
             return {
                 exists: true,
                 framework,
                 language: 'go',
                 path: relativePath,
                 projectPath: basePath,
-                port: 8000
+                port: ports[0] || 8080,
+                ports: ports.length > 0 ? ports : [8080],
+                lockFile: fs.existsSync(path.join(basePath, 'go.sum')) ? 'go.sum' : undefined,
+                vendored: fs.existsSync(path.join(basePath, 'vendor', 'modules.txt')) || undefined,
+                healthCheckPath: this.selectHealthCheckRoute(routes) || '/health',
+                routes: routes.length > 0 ? routes : undefined,
+                languageVersion: this.detectGoVersion(goMod),
+                entryPoint: mainPackages[0] || '.',
+                entryPoints: mainPackages.length > 1 ? mainPackages : undefined,
+                tlsClient: this.detectGoTlsClient(basePath),
+                privateDependencies: this.detectGoPrivateModules(goMod),
+                buildTags: this.detectGoBuildTags(basePath, mainPackages)
             };
         }
 
@@ -767,7 +1740,7 @@ export class EnhancedDetectionEngine {
         const gradleKtsPath = path.join(basePath, 'build.gradle.kts');
         const sbtPath = path.join(basePath, 'build.sbt');
 
-        if (fs.existsSync(pomPath) || fs.existsSync(gradlePath) || fs.existsSync(gradleKtsPath) || fs.existsSync(sbtPath)) {
+        if (wants('java') && (fs.existsSync(pomPath) || fs.existsSync(gradlePath) || fs.existsSync(gradleKtsPath) || fs.existsSync(sbtPath))) {
             let framework = 'java-spring-boot';
             let language: any = 'java';
             let packageManager = 'maven';
@@ -788,6 +1761,14 @@ export class EnhancedDetectionEngine {
                 framework = 'scala-play'; // assumption
             }
 
+            // Gradle builds need every build script that exists (settings, wrapper properties)
+            const dependencyFile = packageManager === 'gradle'
+                ? ['settings.gradle', 'settings.gradle.kts', 'build.gradle', 'build.gradle.kts', 'gradle.properties']
+                    .filter(f => fs.existsSync(path.join(basePath, f))).join(' ')
+                : packageManager === 'maven' ? 'pom.xml' : undefined;
+
+            const port = this.detectSpringPort(basePath);
+
             return {
                 exists: true,
                 framework,
@@ -795,13 +1776,17 @@ export class EnhancedDetectionEngine {
                 packageManager,
                 path: relativePath,
                 projectPath: basePath,
-                port: 8080
+                port,
+                ports: [port],
+                dependencyFile,
+                languageVersion: this.detectJavaVersion(basePath),
+                healthCheckPath: framework === 'java-spring-boot' ? '/actuator/health' : '/health'
             };
         }
 
         // Check for PHP backend
         const composerPath = path.join(basePath, 'composer.json');
-        if (fs.existsSync(composerPath)) {
+        if (wants('php') && fs.existsSync(composerPath)) {
             let composer: any = {};
             try {
                 composer = JSON.parse(fs.readFileSync(composerPath, 'utf-8'));
@@ -828,7 +1813,7 @@ export class EnhancedDetectionEngine {
         // Check for Ruby backend (Rails/Sinatra)
         // Enhanced detection for Ruby on Rails with multiple signals
         const gemfilePath = path.join(basePath, 'Gemfile');
-        if (fs.existsSync(gemfilePath)) {
+        if (wants('ruby') && fs.existsSync(gemfilePath)) {
             const gemfile = fs.readFileSync(gemfilePath, 'utf-8');
             let framework = 'ruby-rails';
             let isRails = false;
@@ -876,7 +1861,7 @@ export class EnhancedDetectionEngine {
 
         // Check for Rust backend
         const cargoPath = path.join(basePath, 'Cargo.toml');
-        if (fs.existsSync(cargoPath)) {
+        if (wants('rust') && fs.existsSync(cargoPath)) {
             const cargo = fs.readFileSync(cargoPath, 'utf-8');
             let framework = 'rust-actix'; // Default or most popular
 
@@ -891,15 +1876,18 @@ export class EnhancedDetectionEngine {
                 packageManager: 'cargo',
                 path: relativePath,
                 projectPath: basePath,
-                port: 8080
+                port: 8080,
+                binaryName: this.detectCargoBinaryName(cargo) || 'app',
+                languageVersion: cargo.match(/^rust-version\s*=\s*"(\d+\.\d+)(?:\.\d+)?"/m)?.[1],
+                lockFile: fs.existsSync(path.join(basePath, 'Cargo.lock')) ? 'Cargo.lock' : undefined,
+                healthCheckPath: '/health'
             };
         }
 
         // Check for .NET backend
-        // Look for .csproj or .fsproj files
-        const files = fs.readdirSync(basePath);
-        const dotnetFile = files.find(f => f.endsWith('.csproj') || f.endsWith('.fsproj'));
-        if (dotnetFile) {
+        // A .csproj/.fsproj in the directory, or a .sln listing the projects to build
+        const dotnet = wants('dotnet') ? this.detectDotnetProject(basePath) : undefined;
+        if (dotnet) {
             return {
                 exists: true,
                 framework: 'dotnet',
@@ -907,13 +1895,19 @@ export class EnhancedDetectionEngine {
                 packageManager: 'nuget',
                 path: relativePath,
                 projectPath: basePath,
-                port: 5000 /* 8080 or 5000 */
+                port: dotnet.port,
+                ports: [dotnet.port],
+                entryPoint: dotnet.projectFile,
+                dependencyFile: dotnet.restoreFiles.join(' '),
+                binaryName: dotnet.assemblyName,
+                languageVersion: dotnet.version,
+                healthCheckPath: '/health'
             };
         }
 
         // Check for Elixir (Phoenix)
         const mixPath = path.join(basePath, 'mix.exs');
-        if (fs.existsSync(mixPath)) {
+        if (wants('elixir') && fs.existsSync(mixPath)) {
             return {
                 exists: true,
                 framework: 'elixir-phoenix',
@@ -926,8 +1920,8 @@ export class EnhancedDetectionEngine {
         }
 
         // Check for Haskell
-        const cabalFile = files.find(f => f.endsWith('.cabal'));
-        if (cabalFile || fs.existsSync(path.join(basePath, 'stack.yaml')) || fs.existsSync(path.join(basePath, 'package.yaml'))) {
+        const cabalFile = fs.readdirSync(basePath).find(f => f.endsWith('.cabal'));
+        if (wants('haskell') && (cabalFile || fs.existsSync(path.join(basePath, 'stack.yaml')) || fs.existsSync(path.join(basePath, 'package.yaml')))) {
             return {
                 exists: true,
                 framework: 'haskell-servant', // assumption
@@ -951,72 +1945,100 @@ export class EnhancedDetectionEngine {
 
     /**
      * Detect databases from environment files, docker-compose, and dependencies
+     * Dependency files are read at the root and in every backend directory (monorepo services)
      */
-    private async detectDatabases(): Promise<DetectedDatabase[]> {
+    private async detectDatabases(serviceDirs: string[] = []): Promise<DetectedDatabase[]> {
         const databases: DetectedDatabase[] = [];
-
-        // 1. Check for database dependencies in package.json (Node.js)
-        const packageJsonPath = path.join(this.basePath, 'package.json');
-        if (fs.existsSync(packageJsonPath)) {
-            try {
-                const packageJson = JSON.parse(fs.readFileSync(packageJsonPath, 'utf-8'));
-                const allDeps = { ...packageJson.dependencies, ...packageJson.devDependencies };
-
-                // PostgreSQL
-                if (allDeps['pg'] || allDeps['postgres'] || allDeps['typeorm'] || allDeps['sequelize'] || allDeps['knex']) {
-                    if (!databases.some(db => db.type === 'postgres')) {
-                        databases.push({ exists: true, type: 'postgres', port: 5432, version: '15-alpine' });
+        const dirs = Array.from(new Set(['.', ...serviceDirs]));
+
+        for (const dir of dirs) {
+            const dirPath = path.join(this.basePath, dir);
+
+            // 1. Check for database dependencies in package.json (Node.js)
+            const packageJsonPath = path.join(dirPath, 'package.json');
+            if (fs.existsSync(packageJsonPath)) {
+                try {
+                    const packageJson = JSON.parse(fs.readFileSync(packageJsonPath, 'utf-8'));
+                    const allDeps = { ...packageJson.dependencies, ...packageJson.devDependencies };
+
+                    // PostgreSQL
+                    if (allDeps['pg'] || allDeps['postgres'] || allDeps['typeorm'] || allDeps['sequelize'] || allDeps['knex']) {
+                        if (!databases.some(db => db.type === 'postgres')) {
+                            databases.push({ exists: true, type: 'postgres', port: 5432, version: '15-alpine' });
+                        }
                     }
-                }
 
-                // MySQL/MariaDB
-                if (allDeps['mysql'] || allDeps['mysql2']) {
-                    if (!databases.some(db => db.type === 'mysql')) {
-                        databases.push({ exists: true, type: 'mysql', port: 3306, version: '8-oracle' });
+                    // MySQL/MariaDB
+                    if (allDeps['mysql'] || allDeps['mysql2']) {
+                        if (!databases.some(db => db.type === 'mysql')) {
+                            databases.push({ exists: true, type: 'mysql', port: 3306, version: '8-oracle' });
+                        }
                     }
-                }
 
-                // MongoDB
-                if (allDeps['mongodb'] || allDeps['mongoose']) {
-                    if (!databases.some(db => db.type === 'mongodb')) {
-                        databases.push({ exists: true, type: 'mongodb', port: 27017, version: '7' });
+                    // MongoDB
+                    if (allDeps['mongodb'] || allDeps['mongoose']) {
+                        if (!databases.some(db => db.type === 'mongodb')) {
+                            databases.push({ exists: true, type: 'mongodb', port: 27017, version: '7' });
+                        }
                     }
-                }
 
-                // Redis
-                if (allDeps['redis'] || allDeps['ioredis']) {
-                    if (!databases.some(db => db.type === 'redis')) {
-                        databases.push({ exists: true, type: 'redis', port: 6379, version: '7-alpine' });
+                    // Redis
+                    if (allDeps['redis'] || allDeps['ioredis']) {
+                        if (!databases.some(db => db.type === 'redis')) {
+                            databases.push({ exists: true, type: 'redis', port: 6379, version: '7-alpine' });
+                        }
                     }
+                } catch (err) {
+                    // Ignore JSON parse errors
                 }
-            } catch (err) {
-                // Ignore JSON parse errors
             }
-        }
 
-        // 2. Check for Python dependencies in requirements.txt
-        const requirementsPath = path.join(this.basePath, 'requirements.txt');
-        if (fs.existsSync(requirementsPath)) {
-            const requirements = fs.readFileSync(requirementsPath, 'utf-8').toLowerCase();
+            // 2. Check for Python dependencies in requirements.txt
+            const requirementsPath = path.join(dirPath, 'requirements.txt');
+            if (fs.existsSync(requirementsPath)) {
+                const requirements = fs.readFileSync(requirementsPath, 'utf-8').toLowerCase();
 
-            if ((requirements.includes('psycopg2') || requirements.includes('asyncpg')) && !databases.some(db => db.type === 'postgres')) {
-                databases.push({ exists: true, type: 'postgres', port: 5432, version: '15-alpine' });
-            }
+                if ((requirements.includes('psycopg2') || requirements.includes('asyncpg')) && !databases.some(db => db.type === 'postgres')) {
+                    databases.push({ exists: true, type: 'postgres', port: 5432, version: '15-alpine' });
+                }
 
-            if ((requirements.includes('pymysql') || requirements.includes('mysql-connector')) && !databases.some(db => db.type === 'mysql')) {
-                databases.push({ exists: true, type: 'mysql', port: 3306, version: '8-oracle' });
-            }
+                if ((requirements.includes('pymysql') || requirements.includes('mysql-connector')) && !databases.some(db => db.type === 'mysql')) {
+                    databases.push({ exists: true, type: 'mysql', port: 3306, version: '8-oracle' });
+                }
+
+                if (requirements.includes('pymongo') && !databases.some(db => db.type === 'mongodb')) {
+                    databases.push({ exists: true, type: 'mongodb', port: 27017, version: '7' });
+                }
 
-            if (requirements.includes('pymongo') && !databases.some(db => db.type === 'mongodb')) {
-                databases.push({ exists: true, type: 'mongodb', port: 27017, version: '7' });
+                if (requirements.includes('redis') && !databases.some(db => db.type === 'redis')) {
+                    databases.push({ exists: true, type: 'redis', port: 6379, version: '7-alpine' });
+                }
             }
 
-            if (requirements.includes('redis') && !databases.some(db => db.type === 'redis')) {
-                databases.push({ exists: true, type: 'redis', port: 6379, version: '7-alpine' });
+            // 3. Check for Go driver modules required in go.mod (indirect requirements are not imported by the app)
+            const goModPath = path.join(dirPath, 'go.mod');
+            if (fs.existsSync(goModPath)) {
+                const required = fs.readFileSync(goModPath, 'utf-8').split('\n')
+                    .filter(line => !line.includes('// indirect'))
+                    .map(line => line.trim().replace(/^require\s+/, '').split(/\s+/)[0]);
+                const requires = (modules: string[]) => required.some(m => modules.some(prefix => m === prefix || m.startsWith(`${prefix}/`)));
+
+                if (requires(['github.com/lib/pq', 'github.com/jackc/pgx', 'gorm.io/driver/postgres']) && !databases.some(db => db.type === 'postgres')) {
+                    databases.push({ exists: true, type: 'postgres', port: 5432, version: '15-alpine' });
+                }
+                if (requires(['github.com/go-sql-driver/mysql', 'gorm.io/driver/mysql']) && !databases.some(db => db.type === 'mysql')) {
+                    databases.push({ exists: true, type: 'mysql', port: 3306, version: '8-oracle' });
+                }
+                if (requires(['go.mongodb.org/mongo-driver']) && !databases.some(db => db.type === 'mongodb')) {
+                    databases.push({ exists: true, type: 'mongodb', port: 27017, version: '7' });
+                }
+                if (requires(['github.com/go-redis/redis', 'github.com/redis/go-redis', 'github.com/gomodule/redigo']) && !databases.some(db => db.type === 'redis')) {
+                    databases.push({ exists: true, type: 'redis', port: 6379, version: '7-alpine' });
+                }
             }
         }
 
-        // 3. Check for docker-compose.yml
+        // 4. Check for docker-compose.yml
         const composePaths = [
             path.join(this.basePath, 'docker-compose.yml'),
             path.join(this.basePath, 'docker-compose.yaml')
@@ -1041,7 +2063,7 @@ export class EnhancedDetectionEngine {
             }
         }
 
-        // 4. Check for .env files
+        // 5. Check for .env files
         const envPath = path.join(this.basePath, '.env');
         const envExamplePath = path.join(this.basePath, '.env.example');
 
@@ -1091,30 +2113,698 @@ export class EnhancedDetectionEngine {
         return fs.existsSync(path.join(this.basePath, filename));
     }
 
+    /**
+     * Detect Go toolchain version from the go directive in go.mod
+     * Keeps the patch version when declared (go 1.21.5), otherwise the minor tag (go 1.21);
+     * pre-releases keep their suffix (go 1.22rc1 => golang:1.22rc1-alpine)
+     */
+    private detectGoVersion(goMod: string): string | undefined {
+        const match = goMod.match(/^go\s+(\d+\.\d+(?:\.\d+)?(?:(?:rc|beta)\d+)?)\s*(?:\/\/.*)?$/m);
+        return match ? match[1] : undefined;
+    }
+
+    /**
+     * Detect server.port from Spring application.properties / application.yml
+     * Accepts literal ports and ${PORT:8081} placeholders (the default is used); falls back to 8080
+     */
+    private detectSpringPort(basePath: string): number {
+        const resourcesDir = path.join(basePath, 'src', 'main', 'resources');
+        const toPort = (value: string): number | undefined => {
+            const match = value.trim().replace(/^['"]|['"]$/g, '').match(/^(?:\$\{[A-Za-z0-9_.]+:)?(\d{2,5})\}?$/);
+            return match ? parseInt(match[1], 10) : undefined;
+        };
+
+        const propertiesPath = path.join(resourcesDir, 'application.properties');
+        if (fs.existsSync(propertiesPath)) {
+            const match = fs.readFileSync(propertiesPath, 'utf-8').match(/^\s*server\.port\s*[=:]\s*(.+)$/m);
+            const port = match ? toPort(match[1]) : undefined;
+            if (port) return port;
+        }
+
+        for (const file of ['application.yml', 'application.yaml']) {
+            const ymlPath = path.join(resourcesDir, file);
+            if (!fs.existsSync(ymlPath)) continue;
+            const content = fs.readFileSync(ymlPath, 'utf-8');
+
+            // server.port: 8081 (flattened key) or server:\n  port: 8081 (nested)
+            const match = content.match(/^server\.port\s*:\s*(.+)$/m)
+                || content.match(/^server\s*:\s*\n(?:[ \t]+.*\n)*?[ \t]+port\s*:\s*(.+)$/m);
+            const port = match ? toPort(match[1]) : undefined;
+            if (port) return port;
+        }
+
+        return 8080;
+    }
+
+    /**
+     * Detect the Java release from pom.xml (java.version / maven.compiler.release)
+     * or build.gradle(.kts) (toolchain languageVersion / sourceCompatibility)
+     */
+    private detectJavaVersion(basePath: string): string | undefined {
+        const pomPath = path.join(basePath, 'pom.xml');
+        if (fs.existsSync(pomPath)) {
+            const match = fs.readFileSync(pomPath, 'utf-8')
+                .match(/<(?:java\.version|maven\.compiler\.release|maven\.compiler\.source)>\s*(?:1\.)?(\d+)\s*</);
+            if (match) return match[1];
+        }
+
+        for (const file of ['build.gradle', 'build.gradle.kts']) {
+            const gradlePath = path.join(basePath, file);
+            if (!fs.existsSync(gradlePath)) continue;
+            const match = fs.readFileSync(gradlePath, 'utf-8')
+                .match(/JavaLanguageVersion\.of\(\s*(\d+)\s*\)|sourceCompatibility\s*=\s*(?:JavaVersion\.VERSION_|['"])(?:1[._])?(\d+)/);
+            if (match) return match[1] || match[2];
+        }
+
+        return undefined;
+    }
+
+    /**
+     * Detect an ASP.NET Core project: the project to publish, its assembly name, target framework and port
+     * A .sln is only used when the directory has no project file of its own; its web project is published
+     */
+    private detectDotnetProject(basePath: string): {
+        projectFile: string; restoreFiles: string[]; assemblyName: string; version?: string; port: number;
+    } | undefined {
+        const files = fs.readdirSync(basePath);
+        let projects = files.filter(f => f.endsWith('.csproj') || f.endsWith('.fsproj'));
+
+        const sln = files.find(f => f.endsWith('.sln'));
+        if (projects.length === 0 && sln) {
+            const content = fs.readFileSync(path.join(basePath, sln), 'utf-8');
+            projects = Array.from(content.matchAll(/^Project\("\{[^}]+\}"\)\s*=\s*"[^"]*",\s*"([^"]+\.(?:cs|fs)proj)"/gm))
+                .map(m => m[1].replace(/\\/g, '/'))
+                .filter(p => fs.existsSync(path.join(basePath, p)));
+        }
+        if (projects.length === 0) return undefined;
+
+        // Prefer the web SDK project, then anything that is not a test project
+        const read = (p: string) => fs.readFileSync(path.join(basePath, p), 'utf-8');
+        const projectFile = projects.find(p => read(p).includes('Microsoft.NET.Sdk.Web'))
+            || projects.find(p => !/test/i.test(p))
+            || projects[0];
+        const project = read(projectFile);
+
+        // net8.0 -> 8.0; with several <TargetFrameworks> the newest wins
+        const frameworks = project.match(/<TargetFrameworks?>\s*([^<]+)</)?.[1].split(';') || [];
+        const version = frameworks
+            .map(f => f.trim().match(/^net(?:coreapp)?(\d+\.\d+)$/)?.[1])
+            .filter((v): v is string => !!v)
+            .sort((a, b) => parseFloat(b) - parseFloat(a))[0];
+
+        const assemblyName = project.match(/<AssemblyName>\s*([^<]+?)\s*</)?.[1]
+            || path.basename(projectFile).replace(/\.(cs|fs)proj$/, '');
+
+        // Copied ahead of the source so `dotnet restore` stays cached
+        const restoreFiles = [
+            ...['global.json', 'NuGet.config', 'nuget.config', 'Directory.Build.props', 'Directory.Packages.props']
+                .filter(f => files.includes(f)),
+            ...projects
+        ];
+
+        return {
+            projectFile,
+            restoreFiles,
+            assemblyName,
+            version,
+            port: this.detectDotnetPort(basePath, path.dirname(projectFile), version)
+        };
+    }
+
+    /**
+     * Detect the ASP.NET Core HTTP port
+     * URLs hard-coded in Program.cs win, then ASPNETCORE_URLS / ASPNETCORE_HTTP_PORTS (.env, launchSettings.json),
+     * then the launch profile's applicationUrl; otherwise the image default (8080 on .NET 8+, 80 before)
+     */
+    private detectDotnetPort(basePath: string, projectDir: string, version?: string): number {
+        const portOf = (urls: string): number | undefined => {
+            const http = urls.split(';').find(u => u.trim().startsWith('http://')) || urls.split(';')[0];
+            const match = http.match(/:(\d{2,5})\/?\s*$/) || http.match(/^\s*(\d{2,5})\s*$/);
+            return match ? parseInt(match[1], 10) : undefined;
+        };
+
+        const programPath = path.join(basePath, projectDir, 'Program.cs');
+        if (fs.existsSync(programPath)) {
+            const match = fs.readFileSync(programPath, 'utf-8')
+                .match(/(?:\.Run|UseUrls|Urls\.Add)\(\s*"([^"]+)"|Listen(?:AnyIP|Localhost)\(\s*(\d{2,5})/);
+            const port = match ? (match[2] ? parseInt(match[2], 10) : portOf(match[1])) : undefined;
+            if (port) return port;
+        }
+
+        const envPath = path.join(basePath, '.env');
+        if (fs.existsSync(envPath)) {
+            const match = fs.readFileSync(envPath, 'utf-8').match(/^\s*ASPNETCORE_(?:URLS|HTTP_PORTS)\s*=\s*["']?([^"'\n]+)/m);
+            const port = match ? portOf(match[1]) : undefined;
+            if (port) return port;
+        }
+
+        const launchSettingsPath = path.join(basePath, projectDir, 'Properties', 'launchSettings.json');
+        if (fs.existsSync(launchSettingsPath)) {
+            try {
+                // launchSettings.json is written with comments and trailing commas by some templates
+                const raw = fs.readFileSync(launchSettingsPath, 'utf-8').replace(/^\s*\/\/.*$/gm, '').replace(/,(\s*[}\]])/g, '$1');
+                const profiles = Object.values<any>(JSON.parse(raw).profiles || {});
+                const ordered = [...profiles.filter(p => p.commandName === 'Project'), ...profiles];
+                for (const profile of ordered) {
+                    const env = profile.environmentVariables || {};
+                    const urls = env.ASPNETCORE_URLS || env.ASPNETCORE_HTTP_PORTS || profile.applicationUrl;
+                    const port = typeof urls === 'string' ? portOf(urls) : undefined;
+                    if (port) return port;
+                }
+            } catch {
+                // Ignore malformed launchSettings.json
+            }
+        }
+
+        return version && parseFloat(version) < 8 ? 80 : 8080;
+    }
+
+    /**
+     * Find Go main packages (a `package main` file declaring `func main()`)
+     * Returns build targets relative to the module root: '.' first, then ./cmd/... sorted
+     * Directories that belong to a nested module (their own go.mod) are skipped
+     */
+    private detectGoMainPackages(basePath: string): string[] {
+        const targets = new Set<string>();
+
+        for (const file of this.findSourceFiles(basePath, ['.go'])) {
+            if (file.endsWith('_test.go')) continue;
+
+            let content: string;
+            try {
+                content = fs.readFileSync(file, 'utf-8');
+            } catch {
+                continue;
+            }
+            if (!/^package\s+main\b/m.test(content) || !/^func\s+main\s*\(\s*\)/m.test(content)) continue;
+
+            const relDir = path.relative(basePath, path.dirname(file)).split(path.sep).join('/');
+            if (this.isInNestedGoModule(basePath, relDir)) continue;
+
+            targets.add(relDir ? `./${relDir}` : '.');
+        }
+
+        const result = [...targets].sort((a, b) => (a === '.' ? -1 : b === '.' ? 1 : a.localeCompare(b)));
+        if (result.length > 1) {
+            log.debug(`Found ${result.length} Go main packages: ${result.join(', ')}`);
+        }
+        return result;
+    }
+
+    /**
+     * Whether a directory (relative to the module root) belongs to a nested module with its own go.mod
+     */
+    private isInNestedGoModule(basePath: string, relDir: string): boolean {
+        const segments = relDir ? relDir.split('/') : [];
+        return segments.some((_, i) => fs.existsSync(path.join(basePath, ...segments.slice(0, i + 1), 'go.mod')));
+    }
+
+    /**
+     * Custom tags in the module's //go:build constraints
+     * RULE: A main package whose func main only builds under tags requires them; when the constraints leave
+     * a choice (prod || staging, config_prod.go vs config_dev.go on prod/!prod) the tags are ambiguous
+     */
+    private detectGoBuildTags(basePath: string, mainPackages: string[]): GoBuildTags | undefined {
+        const files: Array<{ file: string; target: string; main: boolean; constraint?: GoBuildConstraint }> = [];
+
+        for (const file of this.findSourceFiles(basePath, ['.go'])) {
+            if (file.endsWith('_test.go')) continue;
+
+            let content: string;
+            try {
+                content = fs.readFileSync(file, 'utf-8');
+            } catch {
+                continue;
+            }
+
+            const relDir = path.relative(basePath, path.dirname(file)).split(path.sep).join('/');
+            if (this.isInNestedGoModule(basePath, relDir)) continue;
+
+            // Build constraints only count above the package clause
+            const header = content.slice(0, Math.max(0, content.search(/^package\s/m)));
+            const expression = header.match(/^\/\/go:build\s+(.+)$/m);
+            files.push({
+                file: path.relative(basePath, file).split(path.sep).join('/'),
+                target: relDir ? `./${relDir}` : '.',
+                main: /^package\s+main\b/m.test(content) && /^func\s+main\s*\(\s*\)/m.test(content),
+                constraint: expression ? parseGoBuildConstraint(expression[1]) : undefined
+            });
+        }
+
+        const constrained = files.filter(f => f.constraint && f.constraint.positive.length + f.constraint.negative.length > 0);
+        if (constrained.length === 0) return undefined;
+
+        const required: Record<string, string[]> = {};
+        const ambiguous: string[] = [];
+        for (const target of mainPackages) {
+            const mains = files.filter(f => f.main && f.target === target);
+            if (mains.some(f => !f.constraint || f.constraint.matches(new Set()))) continue;
+
+            const choices = new Map<string, string[]>();
+            for (const main of mains) {
+                for (const tags of minimalGoTagSets(main.constraint!)) {
+                    choices.set(tags.join(','), tags);
+                }
+            }
+            if (choices.size === 1) {
+                required[target] = [...choices.values()][0];
+            } else if (choices.size > 1) {
+                ambiguous.push(`main package ${target} builds with ${[...choices.keys()].map(tags => `-tags ${tags}`).join(' or ')}`);
+            }
+        }
+
+        // A tag that swaps files in and out (prod vs !prod) picks a variant, unless a main package already decided it
+        const chosen = new Set(Object.values(required).flat());
+        const positive = new Set(constrained.flatMap(f => f.constraint!.positive));
+        const negative = new Set(constrained.flatMap(f => f.constraint!.negative));
+        for (const tag of [...positive].filter(t => negative.has(t) && !chosen.has(t)).sort()) {
+            const withTag = constrained.find(f => f.constraint!.positive.includes(tag))!.file;
+            const withoutTag = constrained.find(f => f.constraint!.negative.includes(tag))!.file;
+            ambiguous.push(`${tag} selects between ${withTag} and ${withoutTag}`);
+        }
+
+        const result: GoBuildTags = {
+            required,
+            optional: [...positive].filter(t => !chosen.has(t) && !negative.has(t)).sort(),
+            ambiguous: ambiguous.length > 0 ? ambiguous.join('; ') : undefined
+        };
+        log.debug(`Go build tags in ${basePath}: required ${JSON.stringify(required)}, optional [${result.optional.join(', ')}]${result.ambiguous ? `, ambiguous: ${result.ambiguous}` : ''}`);
+        return result;
+    }
+
+    /**
+     * Detect routes registered with r.GET("/path", ...) and friends, including r.Group("/prefix") prefixes
+     * Returns "METHOD /full/path" entries in source order
+     */
+    private detectGoRoutes(basePath: string): string[] {
+        const routes: string[] = [];
+        const groupPrefixes = new Map<string, string>();
+        const join = (prefix: string, route: string) =>
+            ('/' + [prefix, route].join('/')).replace(/\/+/g, '/').replace(/(.)\/$/, '$1');
+
+        for (const file of this.findSourceFiles(basePath, ['.go'])) {
+            if (file.endsWith('_test.go')) continue;
+
+            let content: string;
+            try {
+                content = fs.readFileSync(file, 'utf-8');
+            } catch {
+                continue;
+            }
+
+            // api := r.Group("/api"); v1 := api.Group("/v1") => v1 routes live under /api/v1
+            const pattern = /(?:(\w+)\s*:?=\s*)?\b(\w+)\.(Group|GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|Any)\(\s*"([^"]*)"/g;
+            for (const match of content.matchAll(pattern)) {
+                const [, assigned, receiver, method, route] = match;
+                const fullPath = join(groupPrefixes.get(receiver) || '', route);
+                if (method === 'Group') {
+                    if (assigned) groupPrefixes.set(assigned, fullPath);
+                } else {
+                    const entry = `${method === 'Any' ? 'ANY' : method} ${fullPath}`;
+                    if (!routes.includes(entry)) routes.push(entry);
+                }
+            }
+        }
+
+        if (routes.length > 0) {
+            log.debug(`Found ${routes.length} Go routes`);
+        }
+        return routes;
+    }
+
+    /**
+     * Pick the HEALTHCHECK path from detected routes
+     * RULE: /health or /healthz > a GET route that looks like health/status/ping/ready/live > first static GET route
+     */
+    private selectHealthCheckRoute(routes: string[]): string | undefined {
+        const getPaths = routes
+            .filter(r => r.startsWith('GET ') || r.startsWith('ANY '))
+            .map(r => r.slice(4))
+            .filter(p => !/[:*]/.test(p));
+
+        return getPaths.find(p => p === '/health' || p === '/healthz')
+            || getPaths.find(p => /(^|\/)(health|healthz|status|ping|ready|readyz|live|livez)$/i.test(p))
+            || getPaths[0];
+    }
+
+    /**
+     * Detect listening ports from Go source
+     * Looks at .Run(...), http.ListenAndServe(...), .Listen(...)/.Start(...) and ":PORT" literals
+     */
+    private detectGoPorts(basePath: string): number[] {
+        const ports: number[] = [];
+        const addPort = (address: string) => {
+            const match = address.match(/:(\d{2,5})$/);
+            if (!match) return;
+            const port = parseInt(match[1], 10);
+            if (port > 0 && port <= 65535 && !ports.includes(port)) {
+                ports.push(port);
+            }
+        };
+
+        const patterns = [
+            /\.Run\(\s*"([^"]*)"/g,                          // gin: r.Run("0.0.0.0:8080")
+            /ListenAndServe(?:TLS)?\(\s*"([^"]*)"/g,          // net/http: http.ListenAndServe(":8080", ...)
+            /\.(?:Listen|Start)\(\s*"([^"]*)"/g,             // fiber: app.Listen(":3000"), echo: e.Start(":1323")
+            /"(:\d{2,5})"/g                                  // bare ":PORT" literals (addr := ":9090")
+        ];
+
+        for (const file of this.findSourceFiles(basePath, ['.go'])) {
+            if (file.endsWith('_test.go')) continue;
+
+            let content: string;
+            try {
+                content = fs.readFileSync(file, 'utf-8');
+            } catch {
+                continue;
+            }
+
+            for (const pattern of patterns) {
+                for (const match of content.matchAll(pattern)) {
+                    addPort(match[1]);
+                }
+            }
+
+            // gin: r.Run() without an address listens on :8080
+            if (/\.Run\(\s*\)/.test(content) && content.includes('gin')) {
+                addPort(':8080');
+            }
+        }
+
+        return ports;
+    }
+
+    /**
+     * Detect outbound TLS in Go source (HTTP client calls, crypto/tls, https:// URLs, cloud SDKs)
+     * Returns the first match as "<what> in <file>" for the generator's notes
+     */
+    private detectGoTlsClient(basePath: string): string | undefined {
+        for (const file of this.findSourceFiles(basePath, ['.go'])) {
+            if (file.endsWith('_test.go')) continue;
+
+            let content: string;
+            try {
+                content = fs.readFileSync(file, 'utf-8');
+            } catch {
+                continue;
+            }
+
+            for (const pattern of GO_TLS_CLIENT_PATTERNS) {
+                const match = content.match(pattern);
+                if (match) {
+                    return `${match[1] || match[0]} in ${path.relative(basePath, file).split(path.sep).join('/')}`;
+                }
+            }
+        }
+        return undefined;
+    }
+
+    /**
+     * Private Go modules required by go.mod (self-hosted or internal hosts)
+     * RULE: Modules replaced by a local path are never fetched; public forges are never private
+     */
+    private detectGoPrivateModules(goMod: string): PrivateDependencies | undefined {
+        const content = goMod.replace(/\/\/.*$/gm, '');
+        const replacedLocally = new Set([...content.matchAll(/^\s*(?:replace\s+)?(\S+)(?:\s+v\S+)?\s*=>\s*\.{0,2}\//gm)].map(m => m[1]));
+        const required: string[] = [];
+        for (const block of content.matchAll(/^\s*require\s*\(([^)]*)\)/gm)) {
+            required.push(...block[1].split('\n').map(line => line.trim().split(/\s+/)[0]).filter(Boolean));
+        }
+        for (const single of content.matchAll(/^\s*require\s+([^\s(]+)\s+v/gm)) {
+            required.push(single[1]);
+        }
+
+        const isPrivateHost = (host: string) => {
+            if (!host.includes('.') || PUBLIC_GIT_HOSTS.includes(host)) return false;
+            const labels = host.split('.');
+            return labels.some(label => PRIVATE_HOST_LABELS.includes(label)) || SELF_HOSTED_FORGE_LABELS.includes(labels[0]);
+        };
+        const privateModules = required.filter(module => !replacedLocally.has(module) && isPrivateHost(module.split('/')[0]));
+        if (privateModules.length === 0) return undefined;
+
+        const hosts = [...new Set(privateModules.map(module => module.split('/')[0]))].sort();
+        return {
+            secret: 'netrc',
+            goPrivate: hosts.join(','),
+            reason: `go.mod requires ${privateModules[0]}${privateModules.length > 1 ? ` (+${privateModules.length - 1} more)` : ''}`
+        };
+    }
+
+    /**
+     * npm packages from a registry that needs credentials
+     * Looks at .npmrc (service directory, then project root) and the hosts package-lock.json resolves from
+     */
+    private detectPrivateNpmRegistry(basePath: string): PrivateDependencies | undefined {
+        const isPublic = (url: string) => PUBLIC_NPM_REGISTRIES.some(host => url.replace(/^https?:\/\//, '').startsWith(host));
+
+        for (const dir of [...new Set([basePath, this.basePath])]) {
+            const npmrcPath = path.join(dir, '.npmrc');
+            if (!fs.existsSync(npmrcPath)) continue;
+            const npmrc = fs.readFileSync(npmrcPath, 'utf-8').replace(/^\s*[#;].*$/gm, '');
+            const registry = [...npmrc.matchAll(/^\s*(?:@[\w.-]+:)?registry\s*=\s*(\S+)/gm)].map(m => m[1]).find(url => !isPublic(url));
+            if (registry) {
+                return { secret: 'npmrc', reason: `.npmrc uses registry ${registry}` };
+            }
+            if (/(?:^|:)_(?:auth|authToken|password)\s*=/m.test(npmrc)) {
+                return { secret: 'npmrc', reason: '.npmrc carries registry credentials' };
+            }
+        }
+
+        const lockPath = path.join(basePath, 'package-lock.json');
+        if (fs.existsSync(lockPath)) {
+            const lock = fs.readFileSync(lockPath, 'utf-8');
+            const host = [...lock.matchAll(/"resolved":\s*"https?:\/\/([^/"]+)/g)].map(m => m[1]).find(h => !isPublic(h));
+            if (host) {
+                return { secret: 'npmrc', reason: `package-lock.json resolves packages from ${host}` };
+            }
+        }
+        return undefined;
+    }
+
+    /**
+     * Whether a Node entry file creates an HTTP server and starts listening (http.createServer(...).listen(...))
+     */
+    private startsHttpServer(entryPath: string): boolean {
+        try {
+            const content = fs.readFileSync(entryPath, 'utf-8');
+            return /\bcreateServer\s*\(/.test(content) && /\.listen\s*\(/.test(content);
+        } catch {
+            return false;
+        }
+    }
+
+    /**
+     * Detect the port a Node server listens on
+     * Looks at .listen(3000), .listen(process.env.PORT || 3000), .listen({ port: 3000 }) and a
+     * variable passed to listen (const PORT = process.env.PORT || 3000); the entry file is checked first
+     */
+    private detectNodeListenPort(basePath: string, entryPoint: string): number | undefined {
+        const entryPath = path.join(basePath, entryPoint);
+        const files = [entryPath, ...this.findSourceFiles(basePath, ['.js', '.mjs', '.cjs', '.ts'])
+            .filter(f => f !== entryPath && !f.endsWith('.d.ts'))];
+
+        for (const file of files) {
+            let content: string;
+            try {
+                content = fs.readFileSync(file, 'utf-8');
+            } catch {
+                continue;
+            }
+
+            // Literal port, alone or as the fallback of process.env.PORT || N / ?? N
+            const literalPort = (expression: string): number | undefined => {
+                const match = expression.match(/(?:^|\|\||\?\?|\bport\s*:)\s*\(?\s*(\d{2,5})\b/);
+                const port = match ? parseInt(match[1], 10) : NaN;
+                return port > 0 && port <= 65535 ? port : undefined;
+            };
+
+            for (const listen of content.matchAll(/\.listen\(\s*([^,\n]*)/g)) {
+                const argument = listen[1].trim();
+                const port = literalPort(argument);
+                if (port) return port;
+
+                // listen(PORT) / listen({ port }) / listen({ port: PORT }): resolve the variable's declaration
+                const variable = argument.match(/^\{\s*port\s*:\s*([A-Za-z_$][\w$]*)/)?.[1]
+                    || argument.match(/^\{\s*(port)\b/)?.[1]
+                    || argument.match(/^([A-Za-z_$][\w$]*)\s*\)?\s*$/)?.[1];
+                const declaration = variable && content.match(new RegExp(`\\b(?:const|let|var)\\s+${variable.replace(/\$/g, '\\$')}\\s*=\\s*([^;\\n]+)`));
+                const declared = declaration ? literalPort(declaration[1].trim()) : undefined;
+                if (declared) return declared;
+            }
+        }
+        return undefined;
+    }
+
+    /**
+     * Detect the port passed to uvicorn.run(..., port=N) in Python source
+     */
+    private detectUvicornPort(basePath: string): number | undefined {
+        for (const file of this.findSourceFiles(basePath, ['.py'])) {
+            try {
+                const content = fs.readFileSync(file, 'utf-8');
+                const match = content.match(/uvicorn\.run\([^)]*\bport\s*=\s*(\d{2,5})/);
+                if (match) {
+                    return parseInt(match[1], 10);
+                }
+            } catch {
+                continue;
+            }
+        }
+        return undefined;
+    }
+
+    /**
+     * Detect the FastAPI application object (module:variable) for uvicorn
+     */
+    private detectAsgiApp(basePath: string, entryPoint: string): string {
+        const moduleName = entryPoint.replace(/\.py$/, '');
+        try {
+            const content = fs.readFileSync(path.join(basePath, entryPoint), 'utf-8');
+            const match = content.match(/^(\w+)\s*=\s*FastAPI\s*\(/m);
+            if (match) {
+                return `${moduleName}:${match[1]}`;
+            }
+        } catch { /* ignore */ }
+        return `${moduleName}:app`;
+    }
+
+    /**
+     * Detect Python version from .python-version or requires-python in pyproject.toml
+     */
+    private detectPythonVersion(basePath: string, pyproject: string): string | undefined {
+        const versionFile = path.join(basePath, '.python-version');
+        if (fs.existsSync(versionFile)) {
+            const match = fs.readFileSync(versionFile, 'utf-8').match(/(\d+\.\d+)/);
+            if (match) return match[1];
+        }
+        const match = pyproject.match(/requires-python\s*=\s*["'][^"'\d]*(\d+\.\d+)/);
+        return match ? match[1] : undefined;
+    }
+
+    /**
+     * Binary name from Cargo.toml: first [[bin]] name, else [package] name
+     */
+    private detectCargoBinaryName(cargo: string): string | undefined {
+        let section = '';
+        let packageName: string | undefined;
+
+        for (const line of cargo.split(/\r?\n/)) {
+            const header = line.match(/^\s*(\[\[?[^\]]+\]\]?)\s*$/);
+            if (header) {
+                section = header[1].replace(/\s+/g, '');
+                continue;
+            }
+
+            const name = line.match(/^\s*name\s*=\s*"([^"]+)"/);
+            if (!name) continue;
+            if (section === '[[bin]]') return name[1];
+            if (section === '[package]' && !packageName) packageName = name[1];
+        }
+
+        return packageName;
+    }
+
+    /**
+     * Detect environment variables read by source code
+     * Go: os.Getenv/os.LookupEnv, JS/TS: process.env / import.meta.env, Python: os.getenv/os.environ
+     */
+    private detectEnvVars(basePath: string): string[] {
+        const patterns = [
+            /os\.(?:Getenv|LookupEnv)\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)/g,
+            /process\.env\.([A-Za-z_][A-Za-z0-9_]*)/g,
+            /process\.env\[\s*['"`]([A-Za-z_][A-Za-z0-9_]*)['"`]\s*\]/g,
+            /import\.meta\.env\.([A-Za-z_][A-Za-z0-9_]*)/g,
+            /os\.(?:getenv|environ\.get)\(\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]/g,
+            /os\.environ\[\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]\s*\]/g
+        ];
+        const extensions = ['.go', '.js', '.jsx', '.mjs', '.cjs', '.ts', '.tsx', '.vue', '.svelte', '.py'];
+        const found = new Set<string>();
+
+        for (const file of this.findSourceFiles(basePath, extensions)) {
+            if (/(_test\.go|\.(test|spec)\.[jt]sx?)$/.test(file)) continue;
+
+            const content = fs.readFileSync(file, 'utf-8');
+            for (const pattern of patterns) {
+                for (const match of content.matchAll(pattern)) {
+                    if (!BUILTIN_ENV_VARS.includes(match[1])) {
+                        found.add(match[1]);
+                    }
+                }
+            }
+        }
+
+        return [...found].sort();
+    }
+
+    /**
+     * Find source files with the given extensions (skips dependency and build folders)
+     */
+    private findSourceFiles(dir: string, extensions: string[], depth: number = 0): string[] {
+        if (depth > 5) return [];
+        let results: string[] = [];
+
+        try {
+            for (const entry of fs.readdirSync(dir, { withFileTypes: true })) {
+                const fullPath = path.join(dir, entry.name);
+                if (entry.isDirectory()) {
+                    if (['node_modules', 'vendor', '.git', 'testdata', 'dist', 'build'].includes(entry.name)) continue;
+                    results = results.concat(this.findSourceFiles(fullPath, extensions, depth + 1));
+                } else if (extensions.some(ext => entry.name.endsWith(ext))) {
+                    results.push(fullPath);
+                }
+            }
+        } catch (e) { /* ignore */ }
+
+        return results.sort();
+    }
+
     /**
      * Detect package manager from lock files
+     * RULE: pnpm > yarn > npm when several lockfiles are committed
      */
     private detectPackageManager(basePath: string): 'npm' | 'yarn' | 'pnpm' {
-        if (fs.existsSync(path.join(basePath, 'pnpm-lock.yaml'))) {
-            return 'pnpm';
+        const lockFiles: Array<['npm' | 'yarn' | 'pnpm', string]> = [
+            ['pnpm', 'pnpm-lock.yaml'],
+            ['yarn', 'yarn.lock'],
+            ['npm', 'package-lock.json']
+        ];
+        const found = lockFiles.filter(([, file]) => fs.existsSync(path.join(basePath, file)));
+
+        if (found.length === 0) {
+            return 'npm';
         }
-        if (fs.existsSync(path.join(basePath, 'yarn.lock'))) {
-            return 'yarn';
+
+        const [packageManager, lockFile] = found[0];
+        if (found.length > 1) {
+            log.info(`Multiple lockfiles in ${basePath} (${found.map(([, file]) => file).join(', ')}); using ${packageManager}`);
+        } else {
+            log.debug(`Using ${packageManager} (${lockFile})`);
         }
-        return 'npm';
+        return packageManager;
+    }
+
+    /**
+     * The package manager's lock file, if it is committed (npm ci refuses to run without package-lock.json)
+     */
+    private getNodeLockFile(basePath: string, packageManager: 'npm' | 'yarn' | 'pnpm'): string | undefined {
+        const file = packageManager === 'pnpm' ? 'pnpm-lock.yaml' : packageManager === 'yarn' ? 'yarn.lock' : 'package-lock.json';
+        return fs.existsSync(path.join(basePath, file)) ? file : undefined;
     }
 
     /**
      * Get the correct install command based on package manager
+     * RULE: Lockfile installs must be reproducible (npm ci / --frozen-lockfile)
      */
-    private getInstallCommand(packageManager: 'npm' | 'yarn' | 'pnpm', production: boolean = false): string {
+    private getInstallCommand(packageManager: 'npm' | 'yarn' | 'pnpm', production: boolean = false, hasLockFile: boolean = true): string {
         switch (packageManager) {
             case 'npm':
-                return production ? 'npm ci --only=production' : 'npm ci';
+                // npm ci refuses to run without package-lock.json
+                if (!hasLockFile) return production ? 'npm install --omit=dev' : 'npm install';
+                return production ? 'npm ci --omit=dev' : 'npm ci';
             case 'yarn':
-                return production ? 'yarn install --production' : 'yarn install';
+                return production ? 'yarn install --frozen-lockfile --production' : 'yarn install --frozen-lockfile';
             case 'pnpm':
-                return production ? 'pnpm install --prod' : 'pnpm install';
+                return production ? 'pnpm install --frozen-lockfile --prod' : 'pnpm install --frozen-lockfile';
             default:
                 return 'npm install';
         }
@@ -1160,9 +2850,33 @@ export class EnhancedDetectionEngine {
             if (match) outputFolder = match[1];
         }
 
-        // Check for CRA (react-scripts build) - outputs to 'build'
+        // Check for CRA (react-scripts build) - outputs to 'build' unless BUILD_PATH is set in .env
         if (buildScript.includes('react-scripts build')) {
             outputFolder = 'build';
+            if (!buildScript.includes('BUILD_PATH=')) {
+                for (const envFile of ['.env.production', '.env']) {
+                    const envPath = path.join(basePath, envFile);
+                    const match = fs.existsSync(envPath)
+                        ? fs.readFileSync(envPath, 'utf-8').match(/^BUILD_PATH\s*=\s*['"]?([^'"\s]+)/m)
+                        : null;
+                    if (match) {
+                        outputFolder = match[1].replace(/^\.\//, '');
+                        break;
+                    }
+                }
+            }
+        }
+
+        // Check for webpack output.path (path.resolve(__dirname, 'build'))
+        if (/\bwebpack\b/.test(buildScript)) {
+            const webpackConfigPath = ['webpack.config.js', 'webpack.config.cjs', 'webpack.config.ts', 'webpack.prod.js']
+                .map(f => path.join(basePath, f))
+                .find(f => fs.existsSync(f));
+            if (webpackConfigPath) {
+                const match = fs.readFileSync(webpackConfigPath, 'utf-8')
+                    .match(/output\s*:\s*\{[^}]*?path\s*:\s*path\.(?:resolve|join)\(\s*__dirname\s*,\s*['"]([^'"]+)['"]/);
+                if (match) outputFolder = match[1].replace(/^\.\//, '');
+            }
         }
 
         // Check for Next.js static export
@@ -1172,11 +2886,11 @@ export class EnhancedDetectionEngine {
 
         // Check for vite.config.js/ts for custom build output
         try {
-            const viteConfigPath = fs.existsSync(path.join(basePath, 'vite.config.ts'))
-                ? path.join(basePath, 'vite.config.ts')
-                : path.join(basePath, 'vite.config.js');
+            const viteConfigPath = ['vite.config.ts', 'vite.config.js', 'vite.config.mts', 'vite.config.mjs']
+                .map(f => path.join(basePath, f))
+                .find(f => fs.existsSync(f));
 
-            if (fs.existsSync(viteConfigPath)) {
+            if (viteConfigPath) {
                 const viteConfig = fs.readFileSync(viteConfigPath, 'utf-8');
                 const outDirMatch = viteConfig.match(/outDir:\s*['"]([^'"]+)['"]/);
                 if (outDirMatch) {
@@ -1202,7 +2916,8 @@ export class EnhancedDetectionEngine {
         configFiles?: any
     ): FrameworkOutputInfo & { installCommand: string; packageManager: 'npm' | 'yarn' | 'pnpm' } {
         const packageManager = this.detectPackageManager(basePath);
-        const installCommand = this.getInstallCommand(packageManager, false);
+        const hasLockFile = packageManager !== 'npm' || fs.existsSync(path.join(basePath, 'package-lock.json'));
+        const installCommand = this.getInstallCommand(packageManager, false, hasLockFile);
         let buildCommand = this.getBuildCommand(packageManager, 'build');
         let outputFolder = 'dist'; // default
         let variant = undefined;
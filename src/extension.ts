@@ -1,8 +1,7 @@
 import * as vscode from 'vscode';
-import * as path from 'path';
-import * as fs from 'fs';
 import { FileManager } from './fileManager';
-import { DockerGenerationOrchestrator } from './dockerGenerationOrchestrator';
+import { DockerGenerationOrchestrator, GeneratedDockerFiles } from './dockerGenerationOrchestrator';
+import { GenerationOptions } from './deterministicDockerGenerator';
 import {
     MultiWorkspaceManager,
     GenerationLock
@@ -80,19 +79,24 @@ async function analyzeProject(skipPreview: boolean = false): Promise<void> {
                 try {
                     progress.report({ increment: 20, message: "Analyzing project structure..." });
 
-                    const orchestrator = new DockerGenerationOrchestrator(workspaceRoot, outputChannel);
+                    const options = getGenerationOptions();
+                    let orchestrator = new DockerGenerationOrchestrator(workspaceRoot, outputChannel, options);
 
                     // Step 1: Check for conflicts
+                    // Existing Dockerfiles follow autoDocker.existingDockerfile unless the user chooses to overwrite
                     const conflictCheck = await orchestrator.checkForConflicts();
                     if (conflictCheck.hasConflicts) {
                         const choice = await vscode.window.showWarningMessage(
                             `Found existing Docker file(s). Overwrite?`,
                             { modal: true },
                             'Yes, Overwrite',
+                            'Keep Existing Dockerfiles',
                             'Cancel'
                         );
 
-                        if (choice !== 'Yes, Overwrite') {
+                        if (choice === 'Yes, Overwrite') {
+                            orchestrator = new DockerGenerationOrchestrator(workspaceRoot, outputChannel, { ...options, existingDockerfile: 'overwrite' });
+                        } else if (choice !== 'Keep Existing Dockerfiles') {
                             outputChannel.appendLine('⚠️  Generation cancelled by user');
                             return;
                         }
@@ -168,56 +172,43 @@ function showWelcomeMessage(): void {
     });
 }
 
-async function writeGeneratedFiles(workspaceRoot: string, files: any): Promise<void> {
-    // Write main Dockerfile
-    if (files.dockerfile) {
-        const dockerfilePath = path.join(workspaceRoot, 'Dockerfile');
-        fs.writeFileSync(dockerfilePath, files.dockerfile, 'utf-8');
-        outputChannel.appendLine('✅ Written: Dockerfile');
-    }
-
-    // Write frontend Dockerfiles
-    if (files.frontendDockerfiles && files.frontendDockerfiles.length > 0) {
-        for (const f of files.frontendDockerfiles) {
-            const filePath = path.join(workspaceRoot, f.path);
-            const dir = path.dirname(filePath);
-            if (!fs.existsSync(dir)) fs.mkdirSync(dir, { recursive: true });
-            fs.writeFileSync(filePath, f.content, 'utf-8');
-            outputChannel.appendLine(`✅ Written: ${f.path}`);
-        }
-    }
-
-    // Write backend Dockerfiles
-    if (files.backendDockerfiles && files.backendDockerfiles.length > 0) {
-        for (const f of files.backendDockerfiles) {
-            const filePath = path.join(workspaceRoot, f.path);
-            const dir = path.dirname(filePath);
-            if (!fs.existsSync(dir)) fs.mkdirSync(dir, { recursive: true });
-            fs.writeFileSync(filePath, f.content, 'utf-8');
-            outputChannel.appendLine(`✅ Written: ${f.path}`);
-        }
-    }
-
-    // Write docker-compose.yml
-    if (files.dockerCompose) {
-        const composePath = path.join(workspaceRoot, 'docker-compose.yml');
-        fs.writeFileSync(composePath, files.dockerCompose, 'utf-8');
-        outputChannel.appendLine('✅ Written: docker-compose.yml');
-    }
-
-    // Write nginx.conf at root ONLY for single-service frontend projects
-    if (files.nginxConf && (!files.frontendDockerfiles || files.frontendDockerfiles.length === 0)) {
-        const nginxPath = path.join(workspaceRoot, 'nginx.conf');
-        fs.writeFileSync(nginxPath, files.nginxConf, 'utf-8');
-        outputChannel.appendLine('✅ Written: nginx.conf');
-    }
+/**
+ * Read generation options from autoDocker.* settings
+ */
+function getGenerationOptions(): GenerationOptions {
+    const config = vscode.workspace.getConfiguration('autoDocker');
+    const goBuildTags = config.get<string>('goBuildTags', '').split(/[\s,]+/).filter(Boolean);
+    return {
+        goRuntimeImage: config.get<'alpine' | 'scratch'>('goRuntimeImage', 'alpine'),
+        goSingleStage: config.get<boolean>('goSingleStage', false),
+        healthCheckPath: config.get<string>('healthCheckPath', '') || undefined,
+        recursiveScan: config.get<boolean>('recursiveScan', false),
+        runAsRoot: config.get<boolean>('runAsRoot', false),
+        existingDockerfile: config.get<'skip' | 'generated'>('existingDockerfile', 'skip'),
+        makefile: config.get<boolean>('generateMakefile', false),
+        kubernetes: config.get<boolean>('generateKubernetes', false),
+        skipDatabases: !config.get<boolean>('composeDatabases', true),
+        strict: config.get<boolean>('strictLint', false),
+        devOverride: config.get<boolean>('composeDevOverride', false),
+        distroless: config.get<boolean>('distroless', false),
+        incremental: config.get<boolean>('incremental', false),
+        platforms: config.get<string[]>('platforms', []).length > 0 ? config.get<string[]>('platforms', []) : undefined,
+        resourceLimits: config.get<boolean>('composeResourceLimits', false) ? {} : undefined,
+        swarm: config.get<boolean>('composeSwarm', false),
+        templatesDir: config.get<string>('templatesDir', '') || undefined,
+        goBuildTags: goBuildTags.length > 0 ? goBuildTags : undefined,
+        githubWorkflow: config.get<boolean>('githubWorkflow', false)
+            ? {
+                registry: config.get<string>('registry', 'ghcr.io') || undefined,
+                imagePrefix: config.get<string>('imagePrefix', '') || undefined
+            }
+            : undefined
+    };
+}
 
-    // Write .dockerignore
-    if (files.dockerignore) {
-        const dockerignorePath = path.join(workspaceRoot, '.dockerignore');
-        fs.writeFileSync(dockerignorePath, files.dockerignore, 'utf-8');
-        outputChannel.appendLine('✅ Written: .dockerignore');
-    }
+async function writeGeneratedFiles(workspaceRoot: string, files: GeneratedDockerFiles): Promise<void> {
+    const outputs = DockerGenerationOrchestrator.getOutputFiles(files);
+    DockerGenerationOrchestrator.writeOutputFiles(workspaceRoot, outputs, outputChannel);
 }
 
 export function deactivate() {
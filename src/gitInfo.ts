@@ -0,0 +1,116 @@
+/**
+ * Git Metadata
+ *
+ * Reads the remote URL and current commit for OCI image labels.
+ * RULE: Reads .git directly (no git binary needed); anything missing or unreadable yields undefined.
+ */
+
+import * as fs from 'fs';
+import * as path from 'path';
+
+export interface GitInfo {
+    sourceUrl?: string;  // Browsable https URL of the origin remote (credentials stripped)
+    revision?: string;   // Commit checked out at HEAD
+}
+
+/**
+ * Git info for the repository containing dir (searched upwards), or undefined outside a repo
+ */
+export function readGitInfo(dir: string): GitInfo | undefined {
+    const gitDir = findGitDir(path.resolve(dir));
+    if (!gitDir) return undefined;
+
+    // Worktrees keep refs and config in the main repository's git dir
+    const commonDirFile = path.join(gitDir, 'commondir');
+    const commonDir = fs.existsSync(commonDirFile)
+        ? path.resolve(gitDir, fs.readFileSync(commonDirFile, 'utf-8').trim())
+        : gitDir;
+
+    return {
+        sourceUrl: readRemoteUrl(commonDir),
+        revision: readHeadRevision(gitDir, commonDir)
+    };
+}
+
+/**
+ * Normalize a remote URL to https and drop any embedded credentials
+ * git@github.com:org/app.git => https://github.com/org/app
+ */
+function toSourceUrl(remote: string): string | undefined {
+    const scp = remote.match(/^(?:[\w.-]+@)?([\w.-]+):(?!\/\/)(.+)$/);
+    let url: URL;
+    try {
+        url = new URL(scp ? `https://${scp[1]}/${scp[2]}` : remote);
+    } catch {
+        return undefined;
+    }
+    if (url.protocol === 'file:') return undefined;
+
+    return `https://${url.hostname}${url.pathname.replace(/\.git$/, '').replace(/\/$/, '')}`;
+}
+
+function findGitDir(dir: string): string | undefined {
+    for (let current = dir; ; current = path.dirname(current)) {
+        const candidate = path.join(current, '.git');
+        try {
+            const stat = fs.statSync(candidate);
+            if (stat.isDirectory()) return candidate;
+            // Submodules and worktrees: ".git" is a file with "gitdir: <path>"
+            const pointer = fs.readFileSync(candidate, 'utf-8').match(/^gitdir:\s*(.+)$/m);
+            if (pointer) return path.resolve(current, pointer[1].trim());
+        } catch {
+            // Not here - keep walking up
+        }
+        if (path.dirname(current) === current) return undefined;
+    }
+}
+
+function readRemoteUrl(gitDir: string): string | undefined {
+    let config: string;
+    try {
+        config = fs.readFileSync(path.join(gitDir, 'config'), 'utf-8');
+    } catch {
+        return undefined;
+    }
+
+    // Prefer origin, otherwise the first remote with a url
+    const remotes = new Map<string, string>();
+    let section = '';
+    for (const line of config.split(/\r?\n/)) {
+        const header = line.match(/^\s*\[\s*remote\s+"([^"]+)"\s*\]/);
+        if (header) {
+            section = header[1];
+            continue;
+        }
+        if (/^\s*\[/.test(line)) {
+            section = '';
+            continue;
+        }
+        const url = line.match(/^\s*url\s*=\s*(.+?)\s*$/);
+        if (section && url && !remotes.has(section)) {
+            remotes.set(section, url[1]);
+        }
+    }
+
+    const remote = remotes.get('origin') || remotes.values().next().value;
+    return remote ? toSourceUrl(remote) : undefined;
+}
+
+function readHeadRevision(gitDir: string, commonDir: string): string | undefined {
+    try {
+        const head = fs.readFileSync(path.join(gitDir, 'HEAD'), 'utf-8').trim();
+        const ref = head.match(/^ref:\s*(.+)$/)?.[1];
+        if (!ref) return /^[0-9a-f]{40,64}$/.test(head) ? head : undefined;
+
+        const looseRef = path.join(commonDir, ref);
+        if (fs.existsSync(looseRef)) {
+            return fs.readFileSync(looseRef, 'utf-8').trim();
+        }
+
+        const packed = fs.readFileSync(path.join(commonDir, 'packed-refs'), 'utf-8');
+        return packed.split('\n').find(line => line.endsWith(` ${ref}`))?.split(' ')[0];
+    } catch {
+        // Unborn branch or unreadable refs
+        return undefined;
+    }
+}
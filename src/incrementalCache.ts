@@ -0,0 +1,120 @@
+/**
+ * Incremental Cache
+ *
+ * .autodocker.cache records, per service, a hash of the inputs its files were generated from
+ * and a hash of every file written for it.
+ * RULE: A service is regenerated only when its inputs changed or its files on disk no longer
+ * match what was written; a file whose hash still matches was generated here, not hand-edited.
+ */
+
+import * as crypto from 'crypto';
+import * as fs from 'fs';
+import * as path from 'path';
+import type { DetectedBackend, DetectedFrontend } from './enhancedDetectionEngine';
+import type { GenerationOptions } from './deterministicDockerGenerator';
+
+export const CACHE_FILE = '.autodocker.cache';
+export const CACHE_VERSION = 1;
+
+export interface CacheEntry {
+    inputs: string;                  // Hash of the service's detection result, manifests and entry source
+    files: Record<string, string>;   // Generated file path -> content hash
+}
+
+export interface IncrementalCache {
+    version: number;
+    services: Record<string, CacheEntry>;  // Keyed by service path ("." for the root)
+}
+
+/**
+ * Manifests and build configs that shape a service's Dockerfile
+ * Lockfiles are left out - their content never changes the generated files
+ */
+const INPUT_FILES = [
+    'package.json', 'angular.json', 'vite.config.ts', 'vite.config.js', 'next.config.js', 'next.config.mjs', 'nuxt.config.ts',
+    'go.mod', 'go.work', '.air.toml',
+    'requirements.txt', 'pyproject.toml', 'Pipfile',
+    'pom.xml', 'build.gradle', 'build.gradle.kts', 'settings.gradle',
+    'Gemfile', 'composer.json', 'Cargo.toml', 'mix.exs', 'Program.cs'
+];
+
+/**
+ * Detection fields that change because of a previous run's output or where the project is checked out, not the project
+ */
+const VOLATILE_FIELDS = new Set(['hasDockerfile', 'dockerfilePort', 'dockerfileCopiesGoWork', 'projectPath']);
+
+/**
+ * Options that decide how files are written rather than what they contain
+ */
+const WRITE_OPTIONS = new Set(['existingDockerfile', 'incremental']);
+
+/**
+ * Cache from the last incremental run; empty when missing, unreadable or from another version
+ */
+export function loadIncrementalCache(root: string): IncrementalCache {
+    try {
+        const cache = JSON.parse(fs.readFileSync(path.join(root, CACHE_FILE), 'utf-8')) as IncrementalCache;
+        if (cache.version === CACHE_VERSION && cache.services && typeof cache.services === 'object') {
+            return cache;
+        }
+    } catch {
+        // First run or a corrupt cache - everything is regenerated
+    }
+    return { version: CACHE_VERSION, services: {} };
+}
+
+export function serializeIncrementalCache(cache: IncrementalCache): string {
+    const services = Object.fromEntries(Object.entries(cache.services).sort(([a], [b]) => a.localeCompare(b)));
+    return JSON.stringify({ version: cache.version, services }, null, 2) + '\n';
+}
+
+export function hashContent(content: string): string {
+    return crypto.createHash('sha256').update(content).digest('hex');
+}
+
+/**
+ * Hash of everything a service's generated files depend on
+ */
+export function hashServiceInputs(root: string, service: DetectedFrontend | DetectedBackend, options: GenerationOptions): string {
+    const hash = crypto.createHash('sha256');
+    const detected = Object.fromEntries(Object.entries(service).filter(([key]) => !VOLATILE_FIELDS.has(key)));
+    const generation = Object.fromEntries(Object.entries(options).filter(([key]) => !WRITE_OPTIONS.has(key)));
+    // RULE: Paths are hashed relative to the root - the same project in another checkout hashes the same
+    if (options.templatesDir) {
+        generation.templatesDir = path.relative(root, path.resolve(root, options.templatesDir));
+    }
+    hash.update(JSON.stringify({ version: CACHE_VERSION, detected, generation }));
+
+    const dir = path.join(root, service.path);
+    const files = [
+        ...INPUT_FILES.map(name => path.join(dir, name)),
+        ...listFiles(dir, /\.(csproj|fsproj|sln)$/),
+        ...getEntrySources(dir, 'entryPoint' in service ? service.entryPoint : undefined),
+        ...(options.templatesDir ? listFiles(path.resolve(root, options.templatesDir), /\.Dockerfile\.tmpl$/) : []),
+        path.join(root, '.autodocker.yaml')
+    ];
+    for (const file of files) {
+        if (!fs.existsSync(file) || !fs.statSync(file).isFile()) continue;
+        hash.update(path.relative(root, file));
+        hash.update(fs.readFileSync(file));
+    }
+    return hash.digest('hex');
+}
+
+/**
+ * Source the detected entry point lives in: the file itself, or a Go package directory's .go files
+ */
+function getEntrySources(dir: string, entryPoint?: string): string[] {
+    if (!entryPoint) return [];
+    const entry = path.join(dir, entryPoint);
+    if (!fs.existsSync(entry)) return [];
+    return fs.statSync(entry).isDirectory() ? listFiles(entry, /\.go$/) : [entry];
+}
+
+function listFiles(dir: string, pattern: RegExp): string[] {
+    try {
+        return fs.readdirSync(dir).filter(name => pattern.test(name)).sort().map(name => path.join(dir, name));
+    } catch {
+        return [];
+    }
+}
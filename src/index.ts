@@ -0,0 +1,103 @@
+/**
+ * Auto Docker Library API
+ *
+ * Stable entry point for embedding the generator in other tools.
+ * The CLI (cli.ts) is a thin wrapper over these functions.
+ *
+ *   const project = await detect('/path/to/repo');
+ *   const files = await generate(project, { goRuntimeImage: 'scratch' });
+ *   // files['Dockerfile'], files['docker-compose.yml'], ...
+ */
+
+import * as path from 'path';
+import { EnhancedDetectionEngine, EnhancedDetectionResult, DetectionOptions } from './enhancedDetectionEngine';
+import { GenerationOptions } from './deterministicDockerGenerator';
+import { DockerGenerationOrchestrator, GenerationResult } from './dockerGenerationOrchestrator';
+
+/**
+ * A detected project, as returned by detect()
+ * Can also be constructed directly to skip detection.
+ */
+export interface Project {
+    /** Absolute path of the project root; existing .dockerignore/.env.example/.autodocker.yaml are read from here */
+    root: string;
+    /** Everything detection found: frontend(s), backend(s), databases, monorepo layout */
+    detection: EnhancedDetectionResult;
+}
+
+/**
+ * Generation options (same knobs as the autoDocker.* settings)
+ * - goRuntimeImage: 'alpine' (default) or 'scratch' for Go runtime stages
+ * - goSingleStage: keep the Go toolchain in the final image
+ * - healthCheckPath: override the detected HEALTHCHECK path for backends
+ * - baseImages: per-stack builder/runtime images (merged over .autodocker.yaml)
+ * - runAsRoot: skip the unprivileged runtime USER
+ * - existingDockerfile: 'skip' (default), 'overwrite' or 'generated' for services that already have a Dockerfile
+ * - githubWorkflow: { registry, imagePrefix } to also generate .github/workflows/docker.yml
+ * - makefile: also generate a Makefile with docker-build/run/clean targets
+ * - skipDatabases: leave detected databases out of docker-compose.yml
+ * - strict: fail on Dockerfile lint warnings, not just errors
+ * - devOverride: also generate docker-compose.override.yml with source mounts and hot reload
+ * - distroless: gcr.io/distroless runtime stages (Go, Java and Python backends; throws for others)
+ * - platforms: multi-arch targets (['linux/amd64', 'linux/arm64']) - buildx-ready builders and workflow
+ * - incremental: leave services with unchanged inputs and identical files out of the result (.autodocker.cache)
+ * - resourceLimits: { default?, <service>? } compose deploy.resources.limits (merged over .autodocker.yaml `resources:`)
+ * - swarm: docker-compose.yml for docker stack deploy
+ * - templatesDir: directory of <stack>.Dockerfile.tmpl overrides for the built-in templates (relative to Project.root)
+ * - goBuildTags: go build -tags for Go backends instead of the detected ones ([] builds without tags)
+ * - kubernetes: also generate k8s/<service>.yaml with a Deployment and a Service per built service
+ */
+export type Options = GenerationOptions;
+
+/**
+ * Generated file contents keyed by path relative to Project.root
+ */
+export type GeneratedFiles = Record<string, string>;
+
+/**
+ * Detect the stack(s) in dir
+ */
+export async function detect(dir: string, options: DetectionOptions = {}): Promise<Project> {
+    const root = path.resolve(dir);
+    const detection = await new EnhancedDetectionEngine(root, options).detect();
+    return { root, detection };
+}
+
+/**
+ * Generate Docker files for a project
+ * Throws when the generated files fail validation.
+ */
+export async function generate(project: Project, options: Options = {}): Promise<GeneratedFiles> {
+    return toFileMap(await generateResult(project, options));
+}
+
+/**
+ * Generate Docker files and return the full result (warnings, assumptions, blueprint)
+ */
+export async function generateResult(project: Project, options: Options = {}): Promise<GenerationResult> {
+    const orchestrator = new DockerGenerationOrchestrator(project.root, undefined, options);
+    return orchestrator.generate(project.detection);
+}
+
+/**
+ * Flatten a generation result into relative path -> content
+ */
+export function toFileMap(result: GenerationResult): GeneratedFiles {
+    const files: GeneratedFiles = {};
+    for (const f of DockerGenerationOrchestrator.getOutputFiles(result.files)) {
+        files[f.path] = f.content;
+    }
+    return files;
+}
+
+export { lintDockerfile } from './dockerfileLinter';
+export { buildReport, REPORT_SCHEMA_VERSION } from './report';
+export type { Report, ReportService, ReportFile } from './report';
+export { registerDetector, unregisterDetector, getDetectors } from './detectorRegistry';
+export type { Detector, DetectorContext, DetectedService } from './detectorRegistry';
+export { verify } from './verify';
+export { setVerbosity } from './logger';
+export type { VerifyOptions, VerifyResult } from './verify';
+export type { Finding } from './dockerfileLinter';
+export type { EnhancedDetectionResult, DetectionOptions } from './enhancedDetectionEngine';
+export type { GenerationResult } from './dockerGenerationOrchestrator';
@@ -0,0 +1,54 @@
+/**
+ * Logger
+ *
+ * Leveled diagnostics shared by detection and generation.
+ * RULE: info and warn always print; debug (-v) and trace (-vv) are silent unless the verbosity is raised.
+ *
+ *   debug: decisions - which stack won in a directory and why, monorepo and deep-scan choices
+ *   trace: probes - every detector run, the files it looked for and which of them exist
+ */
+
+export type LogLevel = 'info' | 'debug' | 'trace';
+
+const LEVELS: Record<LogLevel, number> = { info: 0, debug: 1, trace: 2 };
+
+let verbosity = 0;
+
+export interface Logger {
+    info(message: string): void;
+    warn(message: string): void;
+    debug(message: string): void;
+    trace(message: string): void;
+    /** Whether messages at level print - guards work done only to build a message */
+    enabled(level: LogLevel): boolean;
+}
+
+/**
+ * 0 = info (default), 1 = debug (-v), 2 = trace (-vv); applies to every logger
+ */
+export function setVerbosity(level: number): void {
+    verbosity = Math.max(0, Math.min(LEVELS.trace, Math.floor(level)));
+}
+
+export function getVerbosity(): number {
+    return verbosity;
+}
+
+/**
+ * Logger whose lines are prefixed with [scope]
+ * Writes through console.log at call time, so the CLI's stderr redirect applies.
+ */
+export function createLogger(scope: string): Logger {
+    const write = (level: LogLevel, message: string) => {
+        if (verbosity >= LEVELS[level]) {
+            console.log(`[${scope}] ${message}`);
+        }
+    };
+    return {
+        info: message => write('info', message),
+        warn: message => console.warn(`[${scope}] ${message}`),
+        debug: message => write('debug', message),
+        trace: message => write('trace', message),
+        enabled: level => verbosity >= LEVELS[level]
+    };
+}
@@ -0,0 +1,197 @@
+/**
+ * Project Config (.autodocker.yaml)
+ *
+ * Optional per-repository overrides read from the project root.
+ * RULE: Config only tunes templates - it never changes the selected blueprint.
+ *
+ * Example:
+ *   images:
+ *     go: registry.internal/golang:1.21     # shorthand for builder
+ *     node:
+ *       builder: registry.internal/node:20-alpine
+ *       runtime: registry.internal/node:20-alpine
+ *     frontend:
+ *       runtime: registry.internal/nginx:alpine
+ *   resources:                               # compose deploy.resources.limits
+ *     default:
+ *       cpus: 0.5
+ *       memory: 512M
+ *     backend:                               # compose service name
+ *       memory: 1G
+ */
+
+import * as fs from 'fs';
+import * as path from 'path';
+
+export const PROJECT_CONFIG_FILES = ['.autodocker.yaml', '.autodocker.yml'];
+
+/**
+ * Stack keys accepted under `images:` (backend languages + frontend)
+ */
+export const KNOWN_STACKS = [
+    'frontend', 'node', 'python', 'go', 'java', 'ruby', 'php', 'dotnet', 'rust', 'elixir'
+] as const;
+
+export type StackKey = typeof KNOWN_STACKS[number];
+
+export interface BaseImageOverride {
+    builder?: string;  // Image for the build stage (toolchain)
+    runtime?: string;  // Image for the final stage
+}
+
+export interface ResourceLimits {
+    cpus?: string;    // CPU share, e.g. 0.5
+    memory?: string;  // Byte size, e.g. 512M
+}
+
+/**
+ * Applied to every service when limits are on; `default` and per-service entries override field by field
+ */
+export const DEFAULT_RESOURCE_LIMITS: Required<ResourceLimits> = { cpus: '0.5', memory: '512M' };
+
+export interface ProjectConfig {
+    images: Partial<Record<StackKey, BaseImageOverride>>;
+    resources: Record<string, ResourceLimits>;  // "default" or a compose service name -> limits
+}
+
+type YamlValue = string | YamlMap;
+interface YamlMap { [key: string]: YamlValue }
+
+/**
+ * Load .autodocker.yaml from basePath (empty config when absent)
+ * Throws with the file name and reason when the config is invalid
+ */
+export function loadProjectConfig(basePath: string): ProjectConfig {
+    const config: ProjectConfig = { images: {}, resources: {} };
+
+    const fileName = PROJECT_CONFIG_FILES.find(f => fs.existsSync(path.join(basePath, f)));
+    if (!fileName) {
+        return config;
+    }
+
+    const raw = parseSimpleYaml(fs.readFileSync(path.join(basePath, fileName), 'utf-8'), fileName);
+
+    for (const [key, value] of Object.entries(raw)) {
+        if (key === 'resources') {
+            if (typeof value === 'string') {
+                throw new Error(`${fileName}: "resources" must be a mapping of service (or default) to limits`);
+            }
+            for (const [service, limits] of Object.entries(value)) {
+                config.resources[service] = toResourceLimits(limits, `${fileName}: resources.${service}`);
+            }
+            continue;
+        }
+        if (key !== 'images') {
+            throw new Error(`${fileName}: unknown top-level key "${key}"`);
+        }
+        if (typeof value === 'string') {
+            throw new Error(`${fileName}: "images" must be a mapping of stack to image`);
+        }
+
+        for (const [stack, image] of Object.entries(value)) {
+            if (!(KNOWN_STACKS as readonly string[]).includes(stack)) {
+                throw new Error(`${fileName}: unknown stack "${stack}" under images (expected one of: ${KNOWN_STACKS.join(', ')})`);
+            }
+            config.images[stack as StackKey] = toImageOverride(image, `${fileName}: images.${stack}`);
+        }
+    }
+
+    console.log(`[ProjectConfig] Loaded ${fileName}`);
+    return config;
+}
+
+/**
+ * A bare string is the builder image; a mapping may set builder and/or runtime
+ */
+function toImageOverride(value: YamlValue, where: string): BaseImageOverride {
+    if (typeof value === 'string') {
+        return { builder: value };
+    }
+
+    const override: BaseImageOverride = {};
+    for (const [stage, image] of Object.entries(value)) {
+        if (stage !== 'builder' && stage !== 'runtime') {
+            throw new Error(`${where}: unknown key "${stage}" (expected builder or runtime)`);
+        }
+        if (typeof image !== 'string' || image === '') {
+            throw new Error(`${where}.${stage} must be an image reference`);
+        }
+        override[stage] = image;
+    }
+    return override;
+}
+
+/**
+ * cpus and/or memory for one service
+ */
+function toResourceLimits(value: YamlValue, where: string): ResourceLimits {
+    if (typeof value === 'string') {
+        throw new Error(`${where} must be a mapping with cpus and/or memory`);
+    }
+
+    const limits: ResourceLimits = {};
+    for (const [key, limit] of Object.entries(value)) {
+        if (key !== 'cpus' && key !== 'memory') {
+            throw new Error(`${where}: unknown key "${key}" (expected cpus or memory)`);
+        }
+        if (typeof limit !== 'string') {
+            throw new Error(`${where}.${key} must be a value`);
+        }
+        const problem = validateResourceLimit(key, limit);
+        if (problem) {
+            throw new Error(`${where}.${key}: ${problem}`);
+        }
+        limits[key] = limit;
+    }
+    return limits;
+}
+
+/**
+ * Why a cpus/memory value is not valid for compose, or undefined when it is
+ */
+export function validateResourceLimit(key: keyof ResourceLimits, value: string): string | undefined {
+    if (key === 'cpus') {
+        return /^\d+(\.\d+)?$/.test(value) && parseFloat(value) > 0 ? undefined : `"${value}" is not a CPU count (e.g. 0.5)`;
+    }
+    return /^\d+(\.\d+)?[bkmg]?$/i.test(value) && parseFloat(value) > 0 ? undefined : `"${value}" is not a memory size (e.g. 512M, 1G)`;
+}
+
+/**
+ * Parse the small YAML subset the config uses: nested mappings of scalars,
+ * # comments, and optionally quoted values. Anything else is rejected.
+ */
+function parseSimpleYaml(content: string, fileName: string): YamlMap {
+    const root: YamlMap = {};
+    const stack: Array<{ indent: number; map: YamlMap }> = [{ indent: -1, map: root }];
+
+    content.split(/\r?\n/).forEach((line, index) => {
+        const text = line.replace(/(^|\s)#.*$/, '').trimEnd();
+        if (text.trim() === '') {
+            return;
+        }
+
+        const match = text.match(/^(\s*)([A-Za-z0-9_.-]+)\s*:(?:\s+(.*))?$/);
+        if (!match) {
+            throw new Error(`${fileName}:${index + 1}: unsupported syntax "${line.trim()}"`);
+        }
+
+        const indent = match[1].length;
+        const key = match[2];
+        const value = (match[3] || '').trim().replace(/^(['"])(.*)\1$/, '$2');
+
+        while (indent <= stack[stack.length - 1].indent) {
+            stack.pop();
+        }
+        const parent = stack[stack.length - 1].map;
+
+        if (value === '') {
+            const child: YamlMap = {};
+            parent[key] = child;
+            stack.push({ indent, map: child });
+        } else {
+            parent[key] = value;
+        }
+    });
+
+    return root;
+}
@@ -0,0 +1,123 @@
+/**
+ * JSON Report
+ *
+ * Machine-readable summary of a run (`--json`) for CI pipelines and other tools.
+ * RULE: Adding a field keeps the schema version; renaming, removing or retyping one bumps it.
+ */
+
+import { DetectedBackend, DetectedFrontend } from './enhancedDetectionEngine';
+import { GenerationResult, OutputFile } from './dockerGenerationOrchestrator';
+
+export const REPORT_SCHEMA_VERSION = 1;
+
+export interface ReportService {
+    role: 'frontend' | 'backend';
+    path: string;                               // Relative to the project root ("." for the root)
+    stack: string;                              // Backend language, or "frontend"
+    framework: string;
+    port?: number;
+    ports?: number[];
+    entryPoint?: string;
+    dependencies?: Record<string, string>;      // package.json dependencies (Node backends)
+    detector?: string;                          // Registered detector that found the service
+}
+
+export interface ReportFile {
+    path: string;                               // Relative to outputRoot (or the project root in dry-run)
+    status: 'planned' | 'written';
+    content?: string;                           // Dry-run only
+}
+
+export interface Report {
+    schemaVersion: number;
+    success: boolean;
+    root?: string;
+    outputRoot?: string;                        // Where files were written (absent in dry-run)
+    dryRun: boolean;
+    projectType?: string;
+    blueprint?: string;
+    services: ReportService[];
+    databases: Array<{ type: string; port?: number }>;
+    files: ReportFile[];
+    skipped: string[];
+    assumptions: string[];
+    warnings: string[];
+    errors: Array<{ path: string; message: string }>;
+}
+
+/**
+ * Report for a finished generation
+ */
+export function buildReport(
+    root: string,
+    result: GenerationResult,
+    outputs: OutputFile[],
+    mode: { dryRun: boolean; outputRoot?: string }
+): Report {
+    const detection = result.detectionResult;
+    const frontends = detection?.monorepo?.frontends || (detection?.frontend ? [detection.frontend] : []);
+    const backends = detection?.monorepo?.backends || (detection?.backend ? [detection.backend] : []);
+
+    return {
+        schemaVersion: REPORT_SCHEMA_VERSION,
+        success: result.errors.length === 0,
+        root,
+        outputRoot: mode.dryRun ? undefined : mode.outputRoot || root,
+        dryRun: mode.dryRun,
+        projectType: detection?.projectType,
+        blueprint: result.deterministicResult?.blueprint.type,
+        services: [...frontends.map(toFrontendService), ...backends.map(toBackendService)]
+            .sort((a, b) => a.path.localeCompare(b.path)),
+        databases: (detection?.databases || []).filter(db => db.exists).map(db => ({ type: db.type, port: db.port })),
+        files: outputs.map(f => mode.dryRun
+            ? { path: f.path, status: 'planned' as const, content: f.content }
+            : { path: f.path, status: 'written' as const }),
+        skipped: result.skipped,
+        assumptions: result.assumptions || [],
+        warnings: result.warnings,
+        errors: result.errors
+    };
+}
+
+/**
+ * Report for a run that stopped before anything was generated
+ */
+export function buildErrorReport(message: string, dryRun: boolean): Report {
+    return {
+        schemaVersion: REPORT_SCHEMA_VERSION,
+        success: false,
+        dryRun,
+        services: [],
+        databases: [],
+        files: [],
+        skipped: [],
+        assumptions: [],
+        warnings: [],
+        errors: [{ path: '.', message }]
+    };
+}
+
+function toFrontendService(frontend: DetectedFrontend): ReportService {
+    return {
+        role: 'frontend',
+        path: frontend.path,
+        stack: 'frontend',
+        framework: frontend.framework,
+        port: frontend.port,
+        detector: frontend.detector
+    };
+}
+
+function toBackendService(backend: DetectedBackend): ReportService {
+    return {
+        role: 'backend',
+        path: backend.path,
+        stack: backend.language,
+        framework: backend.framework,
+        port: backend.port,
+        ports: backend.ports,
+        entryPoint: backend.entryPoint,
+        dependencies: backend.dependencies,
+        detector: backend.detector
+    };
+}
@@ -0,0 +1,113 @@
+/**
+ * GitHub Actions Workflow Template Manager
+ *
+ * Generates .github/workflows/docker.yml that builds and pushes one image per service.
+ * RULE: One job per service, docker/build-push-action, tagged with the git SHA and latest.
+ */
+
+export const WORKFLOW_PATH = '.github/workflows/docker.yml';
+
+export interface WorkflowService {
+    name: string;        // Compose service name (also the job id and image name)
+    context: string;     // Build context relative to the repo root
+    dockerfile: string;  // Dockerfile path relative to the repo root
+    secrets?: string[];  // BuildKit secret ids, each filled from the repository secret of the same name in upper case
+}
+
+export interface WorkflowOptions {
+    registry?: string;     // Registry host (default: ghcr.io)
+    imagePrefix?: string;  // Image namespace under the registry (default: the GitHub repository)
+    platforms?: string[];  // Multi-arch targets for buildx, e.g. linux/amd64 + linux/arm64
+}
+
+export class WorkflowTemplateManager {
+
+    /**
+     * Generate the build-and-push workflow
+     */
+    static generateDockerWorkflow(services: WorkflowService[], options: WorkflowOptions = {}): string {
+        const registry = options.registry || 'ghcr.io';
+        const imagePrefix = options.imagePrefix || '${{ github.repository }}';
+        const jobs = services.map(s => this.generateJob(s, registry, options.platforms)).join('\n\n');
+
+        return `name: Docker
+
+on:
+  push:
+    tags:
+      - '*'
+  workflow_dispatch:
+
+env:
+  REGISTRY: ${registry}
+  IMAGE_PREFIX: ${imagePrefix}
+
+jobs:
+${jobs}
+`;
+    }
+
+    /**
+     * Generate the job for one service
+     * ghcr.io logs in with the workflow token; other registries use REGISTRY_USERNAME/REGISTRY_PASSWORD secrets
+     * RULE: Multi-arch jobs set up QEMU - runtime stages (apk add, pip install) run on the target CPU
+     */
+    private static generateJob(service: WorkflowService, registry: string, platforms?: string[]): string {
+        const ghcr = registry === 'ghcr.io';
+        const username = ghcr ? '${{ github.actor }}' : '${{ secrets.REGISTRY_USERNAME }}';
+        const password = ghcr ? '${{ secrets.GITHUB_TOKEN }}' : '${{ secrets.REGISTRY_PASSWORD }}';
+        const image = `\${{ env.REGISTRY }}/\${{ env.IMAGE_PREFIX }}/${service.name}`;
+        const multiArch = platforms && platforms.length > 0;
+        const qemu = multiArch ? `      - name: Set up QEMU
+        uses: docker/setup-qemu-action@v3
+
+` : '';
+        const platformLine = multiArch ? `
+          platforms: ${platforms?.join(',')}` : '';
+        const secretLines = service.secrets && service.secrets.length > 0 ? `
+          secrets: |
+${service.secrets.map(id => `            ${id}=\${{ secrets.${id.toUpperCase()} }}`).join('\n')}` : '';
+
+        return `  ${service.name}:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+      packages: write
+    steps:
+      - name: Checkout
+        uses: actions/checkout@v4
+
+      # Registries reject upper-case repository names
+      - name: Normalize image prefix
+        run: echo "IMAGE_PREFIX=\${IMAGE_PREFIX,,}" >> "$GITHUB_ENV"
+
+      # Fills the Dockerfile's OCI created/revision labels
+      - name: Build metadata
+        run: echo "BUILD_DATE=$(date -u +%Y-%m-%dT%H:%M:%SZ)" >> "$GITHUB_ENV"
+
+${qemu}      - name: Set up Docker Buildx
+        uses: docker/setup-buildx-action@v3
+
+      - name: Log in to \${{ env.REGISTRY }}
+        uses: docker/login-action@v3
+        with:
+          registry: \${{ env.REGISTRY }}
+          username: ${username}
+          password: ${password}
+
+      - name: Build and push ${service.name}
+        uses: docker/build-push-action@v6
+        with:
+          context: ${service.context}
+          file: ${service.dockerfile}${platformLine}${secretLines}
+          push: true
+          build-args: |
+            BUILD_DATE=\${{ env.BUILD_DATE }}
+            VCS_REF=\${{ github.sha }}
+          tags: |
+            ${image}:\${{ github.sha }}
+            ${image}:latest
+          cache-from: type=gha,scope=${service.name}
+          cache-to: type=gha,mode=max,scope=${service.name}`;
+    }
+}
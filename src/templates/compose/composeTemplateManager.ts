@@ -6,6 +6,7 @@
  */
 
 import { Blueprint } from '../../blueprints/blueprintTypes';
+import type { ResourceLimits } from '../../projectConfig';
 
 export interface ServiceConfig {
     name: string;
@@ -15,6 +16,7 @@ export interface ServiceConfig {
     image?: string;
     port?: number;
     internalPort?: number;
+    additionalPorts?: number[];  // Extra container ports, mapped 1:1 to host ports
     environment?: Record<string, string>;
     volumes?: string[];
     dependsOn?: string[];
@@ -24,34 +26,98 @@ export interface ServiceConfig {
         timeout: string;
         retries: number;
     };
+    limits?: ResourceLimits;     // deploy.resources.limits
+    buildSecrets?: string[];     // BuildKit secret ids for build.secrets (file: ${HOME}/.<id>)
+    buildArgs?: Record<string, string>;  // build.args
+}
+
+/**
+ * Development settings for one service in docker-compose.override.yml
+ */
+export interface DevServiceConfig {
+    name: string;
+    target: string;                        // Dockerfile stage with the toolchain and all dependencies
+    command: string[];
+    workingDir?: string;                   // Module directory when the mount is a whole Go workspace
+    volumes: string[];                     // Source bind mount (+ anonymous volumes that keep image contents)
+    ports?: string[];                      // Replaces the production mapping when the dev server listens elsewhere
+    environment?: Record<string, string>;
 }
 
 export class ComposeTemplateManager {
     
     /**
      * Generate docker-compose.yml from services
+     * RULE: swarm mode (docker stack deploy) gets deploy.restart_policy and an overlay network;
+     * otherwise restart: and container_name: for docker compose
      */
-    static generateCompose(services: ServiceConfig[], blueprint: Blueprint): string {
+    static generateCompose(services: ServiceConfig[], blueprint: Blueprint, options: { swarm?: boolean } = {}): string {
         const version = blueprint.composeVersion || '3.8';
         
-        const serviceBlocks = services.map(s => this.generateServiceBlock(s)).join('\n\n');
+        const serviceBlocks = services.map(s => this.generateServiceBlock(s, !!options.swarm)).join('\n\n');
         const volumes = this.generateVolumes(services);
-        const networks = this.generateNetworks();
+        const secrets = this.generateSecrets(services);
+        const networks = this.generateNetworks(!!options.swarm);
 
         return `version: '${version}'
 
 services:
 ${serviceBlocks}
 
-${volumes}
+${volumes}${secrets}
 ${networks}
 `;
     }
 
+    /**
+     * docker compose's default project name for a directory (lower-case, [a-z0-9_-] only)
+     * Built images are named <project>-<service>
+     */
+    static getProjectName(dir: string): string {
+        const base = dir.replace(/[\\/]+$/, '').split(/[\\/]/).pop() || '';
+        return base.toLowerCase().replace(/[^a-z0-9_-]/g, '').replace(/^[_-]+/, '') || 'app';
+    }
+
+    /**
+     * Generate docker-compose.override.yml
+     * RULE: Standard override semantics - only the keys that differ from docker-compose.yml
+     */
+    static generateDevOverride(services: DevServiceConfig[]): string {
+        const blocks = services.map(service => {
+            const lines = [
+                `  ${service.name}:`,
+                `    build:`,
+                `      target: ${service.target}`,
+                `    command: ${JSON.stringify(service.command).replace(/","/g, '", "')}`
+            ];
+            if (service.workingDir) {
+                lines.push(`    working_dir: ${service.workingDir}`);
+            }
+            if (service.ports && service.ports.length > 0) {
+                // !override replaces the base list instead of appending to it (Compose 2.24.4+)
+                lines.push(`    ports: !override`);
+                service.ports.forEach(p => lines.push(`      - "${p}"`));
+            }
+            if (service.environment && Object.keys(service.environment).length > 0) {
+                lines.push(`    environment:`);
+                Object.entries(service.environment).forEach(([key, value]) => lines.push(`      ${key}: ${value}`));
+            }
+            lines.push(`    volumes:`);
+            service.volumes.forEach(vol => lines.push(`      - ${vol}`));
+            return lines.join('\n');
+        }).join('\n\n');
+
+        return `# Development overrides - \`docker compose up\` merges this file over docker-compose.yml
+# Production only: docker compose -f docker-compose.yml up
+services:
+${blocks}
+`;
+    }
+
     /**
      * Generate individual service block
      */
-    private static generateServiceBlock(service: ServiceConfig): string {
+    private static generateServiceBlock(service: ServiceConfig, swarm: boolean): string {
         const lines: string[] = [`  ${service.name}:`];
 
         // Build or Image
@@ -61,21 +127,32 @@ ${networks}
             if (service.dockerfile) {
                 lines.push(`      dockerfile: ${service.dockerfile}`);
             }
+            if (service.buildArgs && Object.keys(service.buildArgs).length > 0) {
+                lines.push(`      args:`);
+                Object.entries(service.buildArgs).forEach(([key, value]) => lines.push(`        ${key}: ${value}`));
+            }
+            if (service.buildSecrets && service.buildSecrets.length > 0) {
+                lines.push(`      secrets:`);
+                service.buildSecrets.forEach(id => lines.push(`        - ${id}`));
+            }
         } else if (service.image) {
             lines.push(`    image: ${service.image}`);
         }
 
-        // Container name
-        lines.push(`    container_name: ${service.name}`);
-
-        // Restart policy
-        lines.push(`    restart: unless-stopped`);
+        // Container name and restart policy (swarm names tasks itself and restarts via deploy:)
+        if (!swarm) {
+            lines.push(`    container_name: ${service.name}`);
+            lines.push(`    restart: unless-stopped`);
+        }
 
         // Ports
         if (service.port) {
             const internal = service.internalPort || service.port;
             lines.push(`    ports:`);
             lines.push(`      - "${service.port}:${internal}"`);
+            (service.additionalPorts || []).forEach(p => {
+                lines.push(`      - "${p}:${p}"`);
+            });
         }
 
         // Environment
@@ -111,6 +188,21 @@ ${networks}
             lines.push(`      retries: ${service.healthCheck.retries}`);
         }
 
+        // Deploy: restart policy (swarm) and resource limits (both modes)
+        if (swarm || service.limits) {
+            lines.push(`    deploy:`);
+            if (swarm) {
+                lines.push(`      restart_policy:`);
+                lines.push(`        condition: any`);
+            }
+            if (service.limits) {
+                lines.push(`      resources:`);
+                lines.push(`        limits:`);
+                if (service.limits.cpus) lines.push(`          cpus: "${service.limits.cpus}"`);
+                if (service.limits.memory) lines.push(`          memory: ${service.limits.memory}`);
+            }
+        }
+
         // Networks
         lines.push(`    networks:`);
         lines.push(`      - app-network`);
@@ -144,13 +236,24 @@ ${networks}
         return `volumes:\n${volumeLines}`;
     }
 
+    /**
+     * Build secrets section: each id read from the invoking user's home directory
+     */
+    private static generateSecrets(services: ServiceConfig[]): string {
+        const ids = [...new Set(services.flatMap(s => s.buildSecrets || []))].sort();
+        if (ids.length === 0) {
+            return '';
+        }
+        return `\nsecrets:\n${ids.map(id => `  ${id}:\n    file: \${HOME}/.${id}`).join('\n')}\n`;
+    }
+
     /**
      * Generate networks section
      */
-    private static generateNetworks(): string {
+    private static generateNetworks(swarm: boolean): string {
         return `networks:
   app-network:
-    driver: bridge`;
+    driver: ${swarm ? 'overlay' : 'bridge'}`;
     }
 
     /**
@@ -0,0 +1,299 @@
+/**
+ * Custom Dockerfile Templates (--templates-dir)
+ *
+ * A directory of <stack>.Dockerfile.tmpl files replaces the built-in template for that stack
+ * (the stack keys of .autodocker.yaml `images:`). Each file receives the same TemplateContext the
+ * built-in template is rendered from, plus `.builtin` (the built-in Dockerfile) and `.stack`.
+ * RULE: Every file is parsed and checked when the directory is loaded - a broken template fails
+ * the run before anything is generated, with the file name and line.
+ * The built-in templates are TypeScript (templateManager.ts), not .tmpl files - there is no default
+ * file to copy and edit. Start from `{{ .builtin }}` to wrap the default, or write the stack from scratch.
+ *
+ * Syntax (a subset of Go's text/template):
+ *   {{ .port }}  {{ .buildSecret.id }}  {{ $.framework }}   fields ($ is the root inside range/with)
+ *   {{ if .x }} ... {{ else if .y }} ... {{ else }} ... {{ end }}
+ *   {{ range .ports }}EXPOSE {{ . }}{{ else }}...{{ end }}     {{ with .x }}{{ . }}{{ end }}
+ *   {{ eq .packageManager "pnpm" }}  ne  not  and  or  {{ join .ports " " }}  {{ default "npm" .packageManager }}
+ *   "{{- " trims the whitespace before an action, " -}}" the whitespace after; comment actions are dropped
+ */
+
+import * as fs from 'fs';
+import * as path from 'path';
+import { KNOWN_STACKS, StackKey } from '../../projectConfig';
+import type { TemplateContext } from '../templateManager';
+
+export const CUSTOM_TEMPLATE_SUFFIX = '.Dockerfile.tmpl';
+
+/**
+ * What a custom template is rendered with
+ */
+export interface CustomTemplateData extends TemplateContext {
+    builtin: string;   // The Dockerfile the built-in template generates for this service
+    stack: StackKey;
+}
+
+/**
+ * Root fields a template may reference - keyed by CustomTemplateData so the compiler keeps it complete
+ */
+const TEMPLATE_FIELDS: Record<keyof CustomTemplateData, true> = {
+    framework: true, variant: true, packageManager: true, buildCommand: true, installCommand: true, outputFolder: true,
+    buildArgs: true, port: true, ports: true, language: true, backendFramework: true, entryPoint: true,
+    languageVersion: true, dependencyFile: true, lockFile: true, asgiApp: true, healthCheckPath: true,
+    runtimeImage: true, singleStage: true, builderImage: true, runtimeBaseImage: true, runAsRoot: true,
+    binaryName: true, buildScript: true, buildTool: true, vendored: true, buildTags: true, caCertificates: true, goWorkspace: true,
+    crossBuild: true, buildSecret: true, imageSource: true, imageRevision: true, serviceName: true,
+    workingDir: true, envVars: true, builtin: true, stack: true
+};
+
+/**
+ * Functions and the number of arguments they take (-1: two or more)
+ */
+const FUNCTIONS: Record<string, number> = { eq: 2, ne: 2, not: 1, and: -1, or: -1, join: 2, default: 2 };
+
+type Expr =
+    | { kind: 'field'; root: boolean; path: string[] }
+    | { kind: 'literal'; value: string | number | boolean }
+    | { kind: 'call'; name: string; args: Expr[] };
+
+type Node =
+    | { kind: 'text'; text: string }
+    | { kind: 'output'; expr: Expr; line: number }
+    | { kind: 'if'; branches: Array<{ cond?: Expr; body: Node[] }>; line: number }
+    | { kind: 'range' | 'with'; expr: Expr; body: Node[]; elseBody: Node[]; line: number };
+
+export interface CustomTemplate {
+    file: string;   // Path the template was loaded from (used in error messages)
+    nodes: Node[];
+}
+
+/**
+ * Load and check every <stack>.Dockerfile.tmpl in dir
+ * Throws with file:line and the reason for the first invalid template
+ */
+export function loadCustomTemplates(dir: string): Partial<Record<StackKey, CustomTemplate>> {
+    if (!fs.existsSync(dir) || !fs.statSync(dir).isDirectory()) {
+        throw new Error(`Templates directory not found: ${dir}`);
+    }
+
+    const templates: Partial<Record<StackKey, CustomTemplate>> = {};
+    for (const name of fs.readdirSync(dir).sort()) {
+        if (!name.endsWith(CUSTOM_TEMPLATE_SUFFIX)) continue;
+        const stack = name.slice(0, -CUSTOM_TEMPLATE_SUFFIX.length);
+        const file = path.join(dir, name);
+        if (!(KNOWN_STACKS as readonly string[]).includes(stack)) {
+            throw new Error(`${file}: unknown stack "${stack}" (expected one of: ${KNOWN_STACKS.join(', ')})`);
+        }
+        templates[stack as StackKey] = parseTemplate(fs.readFileSync(file, 'utf-8'), file);
+    }
+
+    if (Object.keys(templates).length === 0) {
+        throw new Error(`${dir}: no templates found (expected <stack>${CUSTOM_TEMPLATE_SUFFIX}, e.g. go${CUSTOM_TEMPLATE_SUFFIX})`);
+    }
+    return templates;
+}
+
+/**
+ * Render a loaded template; execution errors carry the template's file:line
+ */
+export function renderCustomTemplate(template: CustomTemplate, data: CustomTemplateData): string {
+    return renderNodes(template.nodes, data, data, template.file);
+}
+
+/**
+ * Parse template source into nodes
+ */
+export function parseTemplate(source: string, file: string): CustomTemplate {
+    const nodes: Node[] = [];
+    // Open blocks; the innermost one receives new nodes
+    const blocks: Array<{ node: Node; body: Node[]; line: number }> = [];
+    const target = () => blocks.length > 0 ? blocks[blocks.length - 1].body : nodes;
+    let scoped = 0;  // range/with blocks open - inside them "." is not the root
+
+    let pos = 0;
+    let trimNext = false;
+    while (pos < source.length) {
+        const open = source.indexOf('{{', pos);
+        let text = source.slice(pos, open < 0 ? source.length : open);
+        if (trimNext) text = text.replace(/^\s+/, '');
+        if (open >= 0 && /^\{\{-\s/.test(source.slice(open, open + 4))) text = text.replace(/\s+$/, '');
+        if (text) target().push({ kind: 'text', text });
+        if (open < 0) break;
+
+        const line = source.slice(0, open).split('\n').length;
+        const close = source.indexOf('}}', open + 2);
+        if (close < 0) {
+            throw new Error(`${file}:${line}: unclosed action "{{"`);
+        }
+        let action = source.slice(open + 2, close);
+        trimNext = /\s-$/.test(action);
+        action = action.replace(/^-\s/, '').replace(/\s-$/, '').trim();
+        pos = close + 2;
+
+        if (action.startsWith('/*')) {
+            if (!action.endsWith('*/')) throw new Error(`${file}:${line}: unclosed comment`);
+            continue;
+        }
+
+        const fail = (message: string) => new Error(`${file}:${line}: ${message}`);
+        const keyword = action.split(/\s+/)[0];
+        const argument = action.slice(keyword.length).trim();
+        const innermost = blocks[blocks.length - 1];
+
+        if (keyword === 'if' || keyword === 'range' || keyword === 'with') {
+            if (!argument) throw fail(`"${keyword}" needs a value`);
+            const expr = parseExpr(argument, line, file, scoped > 0);
+            const body: Node[] = [];
+            const node: Node = keyword === 'if'
+                ? { kind: 'if', branches: [{ cond: expr, body }], line }
+                : { kind: keyword, expr, body, elseBody: [], line };
+            target().push(node);
+            blocks.push({ node, body, line });
+            if (keyword !== 'if') scoped++;
+        } else if (keyword === 'else') {
+            if (!innermost) throw fail('"else" without "if", "range" or "with"');
+            const node = innermost.node;
+            if (node.kind === 'if') {
+                if (node.branches[node.branches.length - 1].cond === undefined) throw fail('"else" after "else"');
+                const elseIf = /^if\s/.test(argument);
+                if (argument && !elseIf) throw fail(`unexpected "${argument}" after "else"`);
+                const body: Node[] = [];
+                node.branches.push({ cond: elseIf ? parseExpr(argument.slice(2).trim(), line, file, scoped > 0) : undefined, body });
+                innermost.body = body;
+            } else if (node.kind === 'range' || node.kind === 'with') {
+                if (argument) throw fail(`"else ${argument}" is only allowed in "if"`);
+                if (innermost.body === node.elseBody) throw fail('"else" after "else"');
+                innermost.body = node.elseBody;
+                scoped--;
+            }
+        } else if (keyword === 'end') {
+            if (!innermost) throw fail('"end" without "if", "range" or "with"');
+            const node = blocks.pop()!.node;
+            if ((node.kind === 'range' || node.kind === 'with') && innermost.body !== node.elseBody) scoped--;
+        } else if (['define', 'template', 'block'].includes(keyword)) {
+            throw fail(`"${keyword}" is not supported - use one file per stack`);
+        } else if (action) {
+            target().push({ kind: 'output', expr: parseExpr(action, line, file, scoped > 0), line });
+        } else {
+            throw fail('empty action');
+        }
+    }
+
+    if (blocks.length > 0) {
+        const open = blocks[blocks.length - 1];
+        throw new Error(`${file}:${open.line}: "${open.node.kind}" is never closed with "end"`);
+    }
+    return { file, nodes };
+}
+
+/**
+ * Parse a value: a field, a literal, or a function applied to fields and literals
+ */
+function parseExpr(source: string, line: number, file: string, scoped: boolean): Expr {
+    const fail = (message: string) => new Error(`${file}:${line}: ${message}`);
+    const tokens = source.match(/"(?:[^"\\]|\\.)*"|\S+/g) || [];
+    if (tokens.length === 0) throw fail('missing value');
+    if (tokens.includes('|')) throw fail('pipelines ("|") are not supported - call the function directly, e.g. {{ join .ports " " }}');
+
+    const term = (token: string): Expr => {
+        if (token.startsWith('"')) return { kind: 'literal', value: JSON.parse(token) };
+        if (/^-?\d+(\.\d+)?$/.test(token)) return { kind: 'literal', value: Number(token) };
+        if (token === 'true' || token === 'false') return { kind: 'literal', value: token === 'true' };
+        if (token === '.' || token === '$') return { kind: 'field', root: token === '$', path: [] };
+
+        const match = token.match(/^(\$?)((?:\.[A-Za-z_][A-Za-z0-9_]*)+)$/);
+        if (!match) throw fail(`unexpected "${token}"`);
+        const root = match[1] === '$';
+        const fieldPath = match[2].slice(1).split('.');
+        // Only the root's fields are known; inside range/with "." is the element
+        if ((root || !scoped) && !Object.hasOwn(TEMPLATE_FIELDS, fieldPath[0])) {
+            throw fail(`unknown field ".${fieldPath[0]}" (available: ${Object.keys(TEMPLATE_FIELDS).join(', ')})`);
+        }
+        return { kind: 'field', root, path: fieldPath };
+    };
+
+    const [head, ...args] = tokens;
+    if (Object.hasOwn(FUNCTIONS, head)) {
+        const arity = FUNCTIONS[head];
+        if (arity >= 0 ? args.length !== arity : args.length < 2) {
+            throw fail(`"${head}" takes ${arity >= 0 ? arity : 'two or more'} argument(s), got ${args.length}`);
+        }
+        return { kind: 'call', name: head, args: args.map(term) };
+    }
+    if (args.length > 0) throw fail(`"${head}" is not a function (available: ${Object.keys(FUNCTIONS).join(', ')})`);
+    return term(head);
+}
+
+function renderNodes(nodes: Node[], dot: unknown, root: CustomTemplateData, file: string): string {
+    let out = '';
+    for (const node of nodes) {
+        if (node.kind === 'text') {
+            out += node.text;
+        } else if (node.kind === 'output') {
+            out += print(evaluate(node.expr, dot, root, file, node.line), file, node.line);
+        } else if (node.kind === 'if') {
+            const branch = node.branches.find(b => b.cond === undefined || truthy(evaluate(b.cond, dot, root, file, node.line)));
+            if (branch) out += renderNodes(branch.body, dot, root, file);
+        } else if (node.kind === 'with') {
+            const value = evaluate(node.expr, dot, root, file, node.line);
+            out += truthy(value) ? renderNodes(node.body, value, root, file) : renderNodes(node.elseBody, dot, root, file);
+        } else {
+            const value = evaluate(node.expr, dot, root, file, node.line);
+            if (value !== undefined && value !== null && !Array.isArray(value)) {
+                throw new Error(`${file}:${node.line}: range over ${typeof value} - only lists can be ranged over`);
+            }
+            const items = (value as unknown[] | undefined) || [];
+            out += items.length > 0
+                ? items.map(item => renderNodes(node.body, item, root, file)).join('')
+                : renderNodes(node.elseBody, dot, root, file);
+        }
+    }
+    return out;
+}
+
+function evaluate(expr: Expr, dot: unknown, root: CustomTemplateData, file: string, line: number): unknown {
+    if (expr.kind === 'literal') return expr.value;
+    if (expr.kind === 'field') {
+        let value: unknown = expr.root ? root : dot;
+        for (const key of expr.path) {
+            if (value === undefined || value === null) return undefined;
+            if (typeof value !== 'object') {
+                throw new Error(`${file}:${line}: cannot read ".${key}" of ${typeof value} ${JSON.stringify(value)}`);
+            }
+            value = (value as Record<string, unknown>)[key];
+        }
+        return value;
+    }
+
+    const args = expr.args.map(arg => evaluate(arg, dot, root, file, line));
+    switch (expr.name) {
+        case 'eq': return args[0] === args[1];
+        case 'ne': return args[0] !== args[1];
+        case 'not': return !truthy(args[0]);
+        case 'and': return args.find(a => !truthy(a)) ?? args[args.length - 1];
+        case 'or': return args.find(a => truthy(a)) ?? args[args.length - 1];
+        case 'default': return truthy(args[1]) ? args[1] : args[0];
+        default: {
+            if (args[0] !== undefined && !Array.isArray(args[0])) {
+                throw new Error(`${file}:${line}: join needs a list, got ${typeof args[0]}`);
+            }
+            return ((args[0] as unknown[] | undefined) || []).join(String(args[1]));
+        }
+    }
+}
+
+/**
+ * Go's truth: false, 0, empty strings and lists, and missing values are false
+ */
+function truthy(value: unknown): boolean {
+    if (Array.isArray(value)) return value.length > 0;
+    if (value && typeof value === 'object') return Object.keys(value).length > 0;
+    return !!value;
+}
+
+function print(value: unknown, file: string, line: number): string {
+    if (value === undefined || value === null) return '';
+    if (typeof value === 'object') {
+        throw new Error(`${file}:${line}: cannot print a ${Array.isArray(value) ? 'list' : 'mapping'} - use range or join`);
+    }
+    return String(value);
+}
@@ -0,0 +1,153 @@
+/**
+ * Kubernetes Template Manager
+ *
+ * Generates k8s/<service>.yaml with a Deployment and a Service per built service.
+ * RULE: Names, container ports and environment come from the same service list as docker-compose.yml,
+ * so services reach each other by the same host names and ports inside the cluster.
+ */
+
+import type { ResourceLimits } from '../../projectConfig';
+
+export const KUBERNETES_DIR = 'k8s';
+
+export interface KubernetesService {
+    name: string;                          // DNS-1123 name: Deployment, Service, container and app label
+    image: string;
+    pullIfNotPresent?: boolean;            // Local image (kind load / minikube image load) - never pulled when present
+    containerPort: number;                 // Port the Dockerfile EXPOSEs; the Service listens on the same port
+    additionalPorts?: number[];
+    environment?: Record<string, string>;  // docker-compose.yml environment, ${VAR:-default} interpolation included
+    healthCheckPath?: string;              // HTTP readiness/liveness probe on containerPort
+    limits?: ResourceLimits;
+}
+
+export class KubernetesTemplateManager {
+
+    /**
+     * Generate the Deployment + Service manifest for one service
+     */
+    static generateManifest(service: KubernetesService): string {
+        const ports = [service.containerPort, ...(service.additionalPorts || [])];
+        const portName = (port: number, index: number) => index === 0 ? 'http' : `port-${port}`;
+
+        const container = [
+            `        - name: ${service.name}`,
+            `          image: ${service.image}`,
+            ...(service.pullIfNotPresent ? ['          imagePullPolicy: IfNotPresent'] : []),
+            `          ports:`,
+            ...ports.flatMap((port, i) => [
+                `            - name: ${portName(port, i)}`,
+                `              containerPort: ${port}`
+            ]),
+            ...this.generateEnv(service),
+            ...this.generateProbes(service),
+            ...this.generateResources(service.limits)
+        ];
+
+        return `# ${service.name}: Deployment + Service (generated by Auto Docker)
+# Apply with: kubectl apply -f ${KUBERNETES_DIR}/
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ${service.name}
+  labels:
+    app: ${service.name}
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: ${service.name}
+  template:
+    metadata:
+      labels:
+        app: ${service.name}
+    spec:
+      containers:
+${container.join('\n')}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: ${service.name}
+  labels:
+    app: ${service.name}
+spec:
+  selector:
+    app: ${service.name}
+  ports:
+${ports.map((port, i) => `    - name: ${portName(port, i)}
+      port: ${port}
+      targetPort: ${portName(port, i)}`).join('\n')}
+`;
+    }
+
+    /**
+     * Kubernetes resource name for a compose service name (frontend_1 => frontend-1)
+     */
+    static toResourceName(name: string): string {
+        return name.toLowerCase().replace(/[^a-z0-9-]+/g, '-').replace(/^-+|-+$/g, '').slice(0, 63) || 'app';
+    }
+
+    /**
+     * Container env from the compose environment
+     * RULE: Kubernetes does no ${VAR:-default} interpolation - defaults are inlined; a bare ${VAR} is read
+     * from the optional <service>-env Secret, so the manifest applies before that Secret exists
+     */
+    private static generateEnv(service: KubernetesService): string[] {
+        const entries = Object.entries(service.environment || {});
+        if (entries.length === 0) return [];
+
+        const lines = ['          env:'];
+        for (const [name, raw] of entries) {
+            const value = raw.replace(/^(["'])(.*)\1$/, '$2');
+            if (/^\$\{\w+\}$/.test(value)) {
+                lines.push(
+                    `            - name: ${name}`,
+                    `              valueFrom:`,
+                    `                secretKeyRef:`,
+                    `                  name: ${service.name}-env`,
+                    `                  key: ${name}`,
+                    `                  optional: true`
+                );
+            } else {
+                const resolved = value.replace(/\$\{\w+(?::?-([^}]*))?\}/g, (_, fallback) => fallback || '');
+                lines.push(
+                    `            - name: ${name}`,
+                    `              value: ${JSON.stringify(resolved)}`
+                );
+            }
+        }
+        return lines;
+    }
+
+    /**
+     * Readiness and liveness probes on the health path (httpGet - the image needs no wget/curl)
+     */
+    private static generateProbes(service: KubernetesService): string[] {
+        if (!service.healthCheckPath) return [];
+        const probe = (kind: string, initialDelay: number, period: number) => [
+            `          ${kind}:`,
+            `            httpGet:`,
+            `              path: ${service.healthCheckPath}`,
+            `              port: http`,
+            `            initialDelaySeconds: ${initialDelay}`,
+            `            periodSeconds: ${period}`
+        ];
+        return [...probe('readinessProbe', 5, 10), ...probe('livenessProbe', 15, 20)];
+    }
+
+    /**
+     * resources.limits from the compose deploy.resources.limits (compose 512M is Kubernetes 512Mi)
+     */
+    private static generateResources(limits?: ResourceLimits): string[] {
+        if (!limits || (!limits.cpus && !limits.memory)) return [];
+        const memory = limits.memory?.replace(/^(\d+(?:\.\d+)?)\s*([kmgt])b?$/i, (_, size, unit) => `${size}${unit.toUpperCase()}i`)
+            .replace(/^(\d+)b$/i, '$1');
+        return [
+            `          resources:`,
+            `            limits:`,
+            ...(limits.cpus ? [`              cpu: "${limits.cpus}"`] : []),
+            ...(memory ? [`              memory: ${memory}`] : [])
+        ];
+    }
+}
@@ -0,0 +1,77 @@
+/**
+ * Makefile Template Manager
+ *
+ * Generates a Makefile with docker-build / docker-run / docker-clean targets per service.
+ * RULE: Image names and ports come from the same service list as docker-compose.yml.
+ */
+
+export interface MakefileService {
+    name: string;          // Compose service name
+    context: string;       // Build context relative to the repo root
+    dockerfile: string;    // Dockerfile path relative to the repo root
+    hostPort: number;      // Host side of the compose port mapping
+    containerPort: number; // Port the Dockerfile EXPOSEs
+    additionalPorts?: number[];
+    secrets?: string[];    // BuildKit secret ids, read from $(HOME)/.<id>
+}
+
+export class MakefileTemplateManager {
+
+    /**
+     * Generate the Makefile
+     * Image names follow docker compose's <project>-<service> default so both share images;
+     * project is the compose project name (ComposeTemplateManager.getProjectName), overridable with PROJECT=
+     */
+    static generateMakefile(services: MakefileService[], project: string): string {
+        const names = services.map(s => s.name);
+        const phony = ['docker-build', 'docker-run', 'docker-clean',
+            ...names.flatMap(n => [`docker-build-${n}`, `docker-run-${n}`, `docker-clean-${n}`])];
+
+        // Several services run together through compose; a single service runs directly
+        const runAll = services.length === 1
+            ? `docker-run: docker-run-${names[0]}`
+            : `docker-run: ## Build and start every service with docker compose
+\tdocker compose up --build`;
+
+        const targets = services.map(s => this.generateServiceTargets(s)).join('\n\n');
+
+        return `# Docker targets per service (generated by Auto Docker)
+# Image names match docker compose defaults: <project>-<service>
+
+PROJECT ?= ${project}
+RUN_ARGS ?=
+
+.PHONY: ${phony.join(' ')}
+
+docker-build: ${names.map(n => `docker-build-${n}`).join(' ')} ## Build every service image
+
+${runAll}
+
+docker-clean: ${names.map(n => `docker-clean-${n}`).join(' ')} ## Remove every service container and image
+
+${targets}
+`;
+    }
+
+    /**
+     * Build/run/clean targets for one service
+     */
+    private static generateServiceTargets(service: MakefileService): string {
+        const image = `$(PROJECT)-${service.name}`;
+        const ports = [
+            `-p ${service.hostPort}:${service.containerPort}`,
+            ...(service.additionalPorts || []).map(p => `-p ${p}:${p}`)
+        ].join(' ');
+        const secrets = (service.secrets || []).map(id => `--secret id=${id},src=$(HOME)/.${id} `).join('');
+
+        return `docker-build-${service.name}:
+\tdocker build ${secrets}-t ${image} -f ${service.dockerfile} ${service.context}
+
+docker-run-${service.name}: docker-build-${service.name}
+\tdocker run --rm --name ${image} ${ports} $(RUN_ARGS) ${image}
+
+docker-clean-${service.name}:
+\t-docker rm -f ${image}
+\t-docker rmi ${image}`;
+    }
+}
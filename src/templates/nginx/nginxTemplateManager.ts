@@ -20,8 +20,9 @@ export class NginxTemplateManager {
     /**
      * Generate Nginx configuration
      * RULE: All frontends served via Nginx, backends proxied
+     * listenPort is the port the frontend image's nginx binds (8080 unprivileged, 80 as root)
      */
-    static generateConfig(services: NginxService[]): string {
+    static generateConfig(services: NginxService[], listenPort = 80): string {
         const frontends = services.filter(s => s.type === 'frontend');
         const backends = services.filter(s => s.type === 'backend');
 
@@ -30,24 +31,24 @@ export class NginxTemplateManager {
         }
 
         if (frontends.length === 1 && backends.length === 0) {
-            return this.getSingleFrontendTemplate(frontends[0]);
+            return this.getSingleFrontendTemplate(frontends[0], listenPort);
         }
 
         if (frontends.length === 1 && backends.length > 0) {
-            return this.getFrontendWithBackendTemplate(frontends[0], backends);
+            return this.getFrontendWithBackendTemplate(frontends[0], backends, listenPort);
         }
 
         // Multiple frontends
-        return this.getMultipleFrontendsTemplate(frontends, backends);
+        return this.getMultipleFrontendsTemplate(frontends, backends, listenPort);
     }
 
     /**
      * TEMPLATE: Single Frontend (no backend)
      */
-    private static getSingleFrontendTemplate(frontend: NginxService): string {
+    private static getSingleFrontendTemplate(frontend: NginxService, listenPort: number): string {
         return `# Nginx configuration for single frontend
 server {
-    listen 80;
+    listen ${listenPort};
     server_name localhost;
 
     root /usr/share/nginx/html;
@@ -90,7 +91,7 @@ server {
     /**
      * TEMPLATE: Single Frontend + Backend(s)
      */
-    private static getFrontendWithBackendTemplate(frontend: NginxService, backends: NginxService[]): string {
+    private static getFrontendWithBackendTemplate(frontend: NginxService, backends: NginxService[], listenPort: number): string {
         const backendProxies = backends.map(backend => {
             const port = backend.port || 3000;
             return `
@@ -120,7 +121,7 @@ upstream backend {
 }
 
 server {
-    listen 80;
+    listen ${listenPort};
     server_name localhost;
 
     root /usr/share/nginx/html;
@@ -169,7 +170,7 @@ ${backendProxies}
      * TEMPLATE: Multiple Frontends + Optional Backends
      * CRITICAL: Each frontend gets its own routing path
      */
-    private static getMultipleFrontendsTemplate(frontends: NginxService[], backends: NginxService[]): string {
+    private static getMultipleFrontendsTemplate(frontends: NginxService[], backends: NginxService[], listenPort: number): string {
         // Sort frontends: root path last (most specific first)
         const sortedFrontends = [...frontends].sort((a, b) => {
             if (a.path === '/') return 1;
@@ -223,7 +224,7 @@ ${backendProxies}
 
         return `# Nginx configuration for multiple frontends
 server {
-    listen 80;
+    listen ${listenPort};
     server_name localhost;
 
     # Gzip compression
@@ -14,12 +14,35 @@ export interface TemplateContext {
     buildCommand?: string;
     installCommand?: string;
     outputFolder?: string;
+    buildArgs?: Record<string, string>;  // Public env vars the build inlines into client code (ARG defaults)
     port?: number;
+    ports?: number[];
 
     // Backend context
     language?: 'node' | 'python' | 'java' | 'go' | 'php' | 'dotnet' | 'ruby' | 'elixir' | 'rust' | 'haskell' | 'kotlin' | 'scala';
     backendFramework?: string;
     entryPoint?: string;
+    languageVersion?: string;
+    dependencyFile?: string;
+    lockFile?: string;
+    asgiApp?: string;
+    healthCheckPath?: string;
+    runtimeImage?: 'alpine' | 'scratch' | 'distroless';  // distroless: Go, Java and Python only (no shell in the final stage)
+    singleStage?: boolean;
+    builderImage?: string;      // .autodocker.yaml override for the build stage
+    runtimeBaseImage?: string;  // .autodocker.yaml override for the final stage
+    runAsRoot?: boolean;        // Skip the unprivileged runtime user
+    binaryName?: string;        // Compiled binary name (Rust) or assembly name (.NET)
+    buildScript?: boolean;      // Node: package.json build script - always run in the builder
+    buildTool?: 'maven' | 'gradle'; // Java build tool selected from the build file
+    vendored?: boolean;         // Go modules vendored in vendor/ - build offline with -mod=vendor
+    buildTags?: string[];       // Go build tags passed to go build -tags
+    caCertificates?: boolean;   // Go binary makes outbound TLS calls - scratch needs the CA bundle
+    goWorkspace?: { modulePath: string; modules: string[] };  // go.work member: context is the workspace root
+    crossBuild?: boolean;       // Multi-arch: builder stage on $BUILDPLATFORM (Go cross-compiles via TARGETOS/TARGETARCH)
+    buildSecret?: { id: 'netrc' | 'npmrc'; goPrivate?: string; reason: string };  // Private dependencies: BuildKit secret for the install step
+    imageSource?: string;       // org.opencontainers.image.source (git remote URL)
+    imageRevision?: string;     // Default for the VCS_REF build arg (git HEAD commit)
 
     // Common
     serviceName?: string;
@@ -37,6 +60,88 @@ export class TemplateManager {
      * RULE: Templates only - no dynamic generation
      */
     static getFrontendTemplate(context: TemplateContext): string {
+        return this.withBuildSecretHeader(this.withImageLabels(this.selectFrontendTemplate(context), context), context);
+    }
+
+    /**
+     * Get backend Dockerfile template
+     */
+    static getBackendTemplate(context: TemplateContext): string {
+        return this.withBuildSecretHeader(this.withImageLabels(this.selectBackendTemplate(context), context), context);
+    }
+
+    /**
+     * Dockerfile syntax directive and the docker build --secret invocation for private dependencies
+     * RULE: The directive must be the first line of the file
+     */
+    private static withBuildSecretHeader(dockerfile: string, context: TemplateContext): string {
+        if (!context.buildSecret) return dockerfile;
+        const { id, reason } = context.buildSecret;
+
+        return `# syntax=docker/dockerfile:1
+# Private dependencies (${reason}) are installed with a BuildKit secret that never lands in a layer:
+#   docker build --secret id=${id},src=$HOME/.${id} .
+${dockerfile}`;
+    }
+
+    /**
+     * ARGs for env vars the frontend build inlines into the bundle - ARG values are in the RUN environment
+     */
+    private static getBuildArgSteps(context: TemplateContext): string {
+        const args = Object.entries(context.buildArgs || {});
+        if (args.length === 0) return '';
+        return `# Inlined into the bundle at build time, so the browser uses these URLs (override with --build-arg)
+${args.map(([name, value]) => `ARG ${name}=${value}`).join('\n')}
+
+`;
+    }
+
+    /**
+     * RUN --mount for the secret a private dependency install needs (empty when there is none)
+     */
+    private static getSecretMount(context: TemplateContext, id: 'netrc' | 'npmrc'): string {
+        return context.buildSecret?.id === id ? `--mount=type=secret,id=${id},target=/root/.${id} ` : '';
+    }
+
+    /**
+     * GOPRIVATE/GONOSUMDB build args and git for direct fetches of private modules
+     */
+    private static getGoPrivateSetup(context: TemplateContext): string {
+        const goPrivate = context.buildSecret?.id === 'netrc' ? context.buildSecret.goPrivate : undefined;
+        if (!goPrivate) return '';
+
+        return `# Private modules skip the public proxy and checksum DB and are fetched with git over the netrc secret
+ARG GOPRIVATE=${goPrivate}
+ARG GONOSUMDB=\${GOPRIVATE}
+RUN command -v git >/dev/null || apk add --no-cache git
+
+`;
+    }
+
+    /**
+     * Append OCI image labels to the final stage
+     * RULE: Last in the file - the per-build VCS_REF/BUILD_DATE values must not invalidate cached layers
+     */
+    private static withImageLabels(dockerfile: string, context: TemplateContext): string {
+        const labels = [
+            ...(context.imageSource ? [`org.opencontainers.image.source="${context.imageSource.replace(/"/g, '')}"`] : []),
+            'org.opencontainers.image.created="${BUILD_DATE}"',
+            'org.opencontainers.image.revision="${VCS_REF}"'
+        ];
+
+        return `${dockerfile.trimEnd()}
+
+# OCI image labels (CI: --build-arg BUILD_DATE=$(date -u +%Y-%m-%dT%H:%M:%SZ) --build-arg VCS_REF=$(git rev-parse HEAD))
+ARG BUILD_DATE
+ARG VCS_REF${context.imageRevision ? `=${context.imageRevision}` : ''}
+LABEL ${labels.join(' \\\n      ')}
+`;
+    }
+
+    /**
+     * Pick the frontend template for the framework
+     */
+    private static selectFrontendTemplate(context: TemplateContext): string {
         const { framework, variant, packageManager = 'npm', outputFolder = 'dist' } = context;
 
         // Special SSR frameworks
@@ -52,14 +157,19 @@ export class TemplateManager {
             return this.getSvelteKitTemplate(context);
         }
 
+        // Plain HTML (no package.json): nothing to install or build
+        if (framework === 'html') {
+            return this.getStaticHtmlTemplate(context);
+        }
+
         // Default static frontend template
         return this.getStaticFrontendTemplate(context);
     }
 
     /**
-     * Get backend Dockerfile template
+     * Pick the backend template for the language
      */
-    static getBackendTemplate(context: TemplateContext): string {
+    private static selectBackendTemplate(context: TemplateContext): string {
         const { language, backendFramework } = context;
 
         switch (language) {
@@ -92,33 +202,35 @@ export class TemplateManager {
     private static getStaticFrontendTemplate(context: TemplateContext): string {
         const { packageManager = 'npm', installCommand, buildCommand, outputFolder = 'dist' } = context;
 
-        const packageFiles = packageManager === 'yarn' ? 'package.json yarn.lock' :
-            packageManager === 'pnpm' ? 'package.json pnpm-lock.yaml' :
-                'package*.json';
+        const packageFiles = this.getPackageFiles(packageManager);
+        const pmSetup = this.getPackageManagerSetup(packageManager);
 
         const install = installCommand || `${packageManager} install`;
         const build = buildCommand || `${packageManager} run build`;
+        const builderImage = context.builderImage || 'node:20-alpine';
+        const runtimeBase = this.getNginxImage(context);
+        const port = this.getStaticFrontendPort(context.runAsRoot);
 
         return `# Multi-stage build for static frontend
 # Stage 1: Build
-FROM node:20-alpine AS builder
+FROM ${this.getBuilderPlatform(context)}${builderImage} AS builder
 
 WORKDIR /app
 
 # Copy package files
 COPY ${packageFiles} ./
 
-# Install dependencies
-RUN ${install}
+${pmSetup}# Install dependencies
+RUN ${this.getSecretMount(context, 'npmrc')}${install}
 
 # Copy source code
 COPY . .
 
-# Build application
+${this.getBuildArgSteps(context)}# Build application
 RUN ${build}
 
 # Stage 2: Production with Nginx
-FROM nginx:alpine
+FROM ${runtimeBase}
 
 # Copy nginx configuration
 COPY nginx.conf /etc/nginx/conf.d/default.conf
@@ -126,67 +238,113 @@ COPY nginx.conf /etc/nginx/conf.d/default.conf
 # Copy built files from builder
 COPY --from=builder /app/${outputFolder} /usr/share/nginx/html
 
-# Expose port 80
-EXPOSE 80
+# Expose port ${port}
+EXPOSE ${port}
 
 # Health check
 HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \\
-    CMD wget --quiet --tries=1 --spider http://localhost/ || exit 1
+    CMD wget --quiet --tries=1 --spider http://localhost:${port}/ || exit 1
 
 # Start nginx
 CMD ["nginx", "-g", "daemon off;"]
 `;
     }
 
+    /**
+     * TEMPLATE: Static HTML site (no package.json)
+     * RULE: No builder stage - the files are served as they are
+     */
+    private static getStaticHtmlTemplate(context: TemplateContext): string {
+        const runtimeBase = this.getNginxImage(context);
+        const port = this.getStaticFrontendPort(context.runAsRoot);
+
+        return `# Static HTML site served by Nginx (no build step)
+FROM ${runtimeBase}
+
+# Copy nginx configuration
+COPY nginx.conf /etc/nginx/conf.d/default.conf
+
+# Copy site files
+COPY . /usr/share/nginx/html
+
+# Expose port ${port}
+EXPOSE ${port}
+
+# Health check
+HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \\
+    CMD wget --quiet --tries=1 --spider http://localhost:${port}/ || exit 1
+
+# Start nginx
+CMD ["nginx", "-g", "daemon off;"]
+`;
+    }
+
+    /**
+     * Nginx image for static frontends: the unprivileged build unless the image should keep root
+     */
+    private static getNginxImage(context: TemplateContext): string {
+        return context.runtimeBaseImage || (context.runAsRoot ? 'nginx:alpine' : 'nginxinc/nginx-unprivileged:alpine');
+    }
+
+    /**
+     * Port nginx listens on in a static frontend image
+     * RULE: Unprivileged nginx runs as the nginx user and cannot bind below 1024; only --root keeps port 80
+     */
+    static getStaticFrontendPort(runAsRoot?: boolean): number {
+        return runAsRoot ? 80 : 8080;
+    }
+
     /**
      * TEMPLATE: Next.js SSR
      */
     private static getNextJsSSRTemplate(context: TemplateContext): string {
         const { packageManager = 'npm', installCommand } = context;
 
-        const packageFiles = packageManager === 'yarn' ? 'package.json yarn.lock' :
-            packageManager === 'pnpm' ? 'package.json pnpm-lock.yaml' :
-                'package*.json';
+        const packageFiles = this.getPackageFiles(packageManager);
+        const pmSetup = this.getPackageManagerSetup(packageManager);
 
         const install = installCommand || `${packageManager} install`;
+        const builderImage = context.builderImage || 'node:20-alpine';
+        const runtimeBase = context.runtimeBaseImage || 'node:20-alpine';
+        const user = this.getRuntimeUser(context, 'node');
 
         return `# Multi-stage build for Next.js SSR
 # Stage 1: Dependencies
-FROM node:20-alpine AS deps
+FROM ${builderImage} AS deps
 
 WORKDIR /app
 
 # Copy package files
 COPY ${packageFiles} ./
 
-# Install dependencies
-RUN ${install}
+${pmSetup}# Install dependencies
+RUN ${this.getSecretMount(context, 'npmrc')}${install}
 
 # Stage 2: Builder
-FROM node:20-alpine AS builder
+FROM ${builderImage} AS builder
 
 WORKDIR /app
 
-# Copy dependencies
+${pmSetup}# Copy dependencies
 COPY --from=deps /app/node_modules ./node_modules
 
 # Copy source
 COPY . .
 
-# Build Next.js application
+${this.getBuildArgSteps(context)}# Build Next.js application
 RUN ${packageManager} run build
 
 # Stage 3: Production
-FROM node:20-alpine AS runner
+FROM ${runtimeBase} AS runner
 
 WORKDIR /app
 
 ENV NODE_ENV=production
 
 # Copy necessary files
-COPY --from=builder /app/public ./public
-COPY --from=builder /app/.next/standalone ./
-COPY --from=builder /app/.next/static ./.next/static
+COPY ${user.chown}--from=builder /app/public ./public
+COPY ${user.chown}--from=builder /app/.next/standalone ./
+COPY ${user.chown}--from=builder /app/.next/static ./.next/static
 
 # Expose port
 EXPOSE 3000
@@ -195,7 +353,7 @@ EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \\
     CMD wget --quiet --tries=1 --spider http://localhost:3000/ || exit 1
 
-# Start application
+${user.switchUser}# Start application
 CMD ["node", "server.js"]
 `;
     }
@@ -206,38 +364,40 @@ CMD ["node", "server.js"]
     private static getNuxtSSRTemplate(context: TemplateContext): string {
         const { packageManager = 'npm', installCommand } = context;
 
-        const packageFiles = packageManager === 'yarn' ? 'package.json yarn.lock' :
-            packageManager === 'pnpm' ? 'package.json pnpm-lock.yaml' :
-                'package*.json';
+        const packageFiles = this.getPackageFiles(packageManager);
+        const pmSetup = this.getPackageManagerSetup(packageManager);
 
         const install = installCommand || `${packageManager} install`;
+        const builderImage = context.builderImage || 'node:20-alpine';
+        const runtimeBase = context.runtimeBaseImage || 'node:20-alpine';
+        const user = this.getRuntimeUser(context, 'node');
 
         return `# Multi-stage build for Nuxt SSR
-FROM node:20-alpine AS builder
+FROM ${builderImage} AS builder
 
 WORKDIR /app
 
 # Copy package files
 COPY ${packageFiles} ./
 
-# Install dependencies
-RUN ${install}
+${pmSetup}# Install dependencies
+RUN ${this.getSecretMount(context, 'npmrc')}${install}
 
 # Copy source
 COPY . .
 
-# Build Nuxt application
+${this.getBuildArgSteps(context)}# Build Nuxt application
 RUN ${packageManager} run build
 
 # Production stage
-FROM node:20-alpine
+FROM ${runtimeBase}
 
 WORKDIR /app
 
 ENV NODE_ENV=production
 
 # Copy built application
-COPY --from=builder /app/.output ./
+COPY ${user.chown}--from=builder /app/.output ./
 
 # Expose port
 EXPOSE 3000
@@ -246,7 +406,7 @@ EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \\
     CMD wget --quiet --tries=1 --spider http://localhost:3000/ || exit 1
 
-# Start Nuxt
+${user.switchUser}# Start Nuxt
 CMD ["node", "server/index.mjs"]
 `;
     }
@@ -257,40 +417,42 @@ CMD ["node", "server/index.mjs"]
     private static getSvelteKitTemplate(context: TemplateContext): string {
         const { packageManager = 'npm', installCommand } = context;
 
-        const packageFiles = packageManager === 'yarn' ? 'package.json yarn.lock' :
-            packageManager === 'pnpm' ? 'package.json pnpm-lock.yaml' :
-                'package*.json';
+        const packageFiles = this.getPackageFiles(packageManager);
+        const pmSetup = this.getPackageManagerSetup(packageManager);
 
         const install = installCommand || `${packageManager} install`;
+        const builderImage = context.builderImage || 'node:20-alpine';
+        const runtimeBase = context.runtimeBaseImage || 'node:20-alpine';
+        const user = this.getRuntimeUser(context, 'node');
 
         return `# Multi-stage build for SvelteKit
-FROM node:20-alpine AS builder
+FROM ${builderImage} AS builder
 
 WORKDIR /app
 
 # Copy package files
 COPY ${packageFiles} ./
 
-# Install dependencies
-RUN ${install}
+${pmSetup}# Install dependencies
+RUN ${this.getSecretMount(context, 'npmrc')}${install}
 
 # Copy source
 COPY . .
 
-# Build SvelteKit application
+${this.getBuildArgSteps(context)}# Build SvelteKit application
 RUN ${packageManager} run build
 
 # Production stage
-FROM node:20-alpine
+FROM ${runtimeBase}
 
 WORKDIR /app
 
 ENV NODE_ENV=production
 
 # Copy built application
-COPY --from=builder /app/build ./build
-COPY --from=builder /app/package.json ./
-COPY --from=builder /app/node_modules ./node_modules
+COPY ${user.chown}--from=builder /app/build ./build
+COPY ${user.chown}--from=builder /app/package.json ./
+COPY ${user.chown}--from=builder /app/node_modules ./node_modules
 
 # Expose port
 EXPOSE 3000
@@ -299,47 +461,134 @@ EXPOSE 3000
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \\
     CMD wget --quiet --tries=1 --spider http://localhost:3000/ || exit 1
 
-# Start SvelteKit
+${user.switchUser}# Start SvelteKit
 CMD ["node", "build"]
 `;
     }
 
     /**
-     * TEMPLATE: Node.js Backend
+     * Manifest + lockfile copied ahead of the source for layer caching
      */
-    private static getNodeBackendTemplate(context: TemplateContext): string {
-        const { packageManager = 'npm', installCommand, entryPoint = 'server.js', port = 3000 } = context;
+    private static getPackageFiles(packageManager: 'npm' | 'yarn' | 'pnpm'): string {
+        if (packageManager === 'pnpm') return 'package.json pnpm-lock.yaml';
+        if (packageManager === 'yarn') return 'package.json yarn.lock';
+        return 'package*.json';
+    }
+
+    /**
+     * node:alpine ships npm and yarn; pnpm has to be enabled through corepack
+     */
+    private static getPackageManagerSetup(packageManager: 'npm' | 'yarn' | 'pnpm'): string {
+        return packageManager === 'pnpm' ? `# Enable pnpm
+RUN corepack enable
+
+` : '';
+    }
+
+    /**
+     * Lockfile-pinned install for a Node backend
+     * RULE: npm ci needs package-lock.json - without it npm falls back to npm install; yarn and pnpm
+     * builds always COPY their lock file, so they always freeze it
+     */
+    private static getNodeInstallCommand(packageManager: 'npm' | 'yarn' | 'pnpm', production: boolean, lockFile?: string): string {
+        if (packageManager === 'pnpm') return production ? 'pnpm install --frozen-lockfile --prod' : 'pnpm install --frozen-lockfile';
+        if (packageManager === 'yarn') return production ? 'yarn install --frozen-lockfile --production' : 'yarn install --frozen-lockfile';
+        if (!lockFile) return production ? 'npm install --omit=dev' : 'npm install';
+        return production ? 'npm ci --omit=dev' : 'npm ci';
+    }
+
+    /**
+     * Unprivileged user for the final stage
+     * RULE: Runtime images never run as root unless runAsRoot is set
+     */
+    private static getRuntimeUser(context: TemplateContext, user: string, createCommand?: string): { create: string; chown: string; switchUser: string } {
+        if (context.runAsRoot) {
+            return { create: '', chown: '', switchUser: '' };
+        }
+
+        return {
+            create: createCommand ? `# Create unprivileged user
+RUN ${createCommand}
+
+` : '',
+            chown: `--chown=${user}:${user} `,
+            switchUser: `# Run as non-root user
+USER ${user}
+
+`
+        };
+    }
+
+    /**
+     * FROM flag for a builder stage whose output does not depend on the CPU
+     * RULE: Multi-arch builds run such stages natively on $BUILDPLATFORM instead of under emulation
+     */
+    private static getBuilderPlatform(context: TemplateContext): string {
+        return context.crossBuild ? '--platform=$BUILDPLATFORM ' : '';
+    }
+
+    /**
+     * ARG/ENV wiring for the container port
+     * RULE: Declared in the final stage - ARGs do not cross FROM boundaries, and ENV keeps PORT visible to the app at run time
+     */
+    private static getPortDeclaration(port: number, extraPorts: number[] = [], extraEnv: string[] = []): string {
+        const extra = extraPorts.length > 0 ? ` ${extraPorts.join(' ')}` : '';
+        // extraEnv entries are NAME (set to the port) or NAME=value
+        const env = ['PORT', ...extraEnv].map(e => e.includes('=') ? `ENV ${e}` : `ENV ${e}=\${PORT}`).join('\n');
+        return `# Port (override with --build-arg PORT=... or -e PORT=...)
+ARG PORT=${port}
+${env}
 
-        const packageFiles = packageManager === 'yarn' ? 'package.json yarn.lock' :
-            packageManager === 'pnpm' ? 'package.json pnpm-lock.yaml' :
-                'package*.json';
+# Expose port
+EXPOSE \${PORT}${extra}`;
+    }
 
-        const install = installCommand || `${packageManager} install --production`;
+    /**
+     * TEMPLATE: Node.js Backend
+     */
+    private static getNodeBackendTemplate(context: TemplateContext): string {
+        const { packageManager = 'npm', installCommand, entryPoint = 'server.js', port = 3000, healthCheckPath = '/health' } = context;
+
+        const packageFiles = this.getPackageFiles(packageManager);
+        const pmSetup = this.getPackageManagerSetup(packageManager);
+
+        const install = installCommand || this.getNodeInstallCommand(packageManager, true, context.lockFile);
+        const builderImage = context.builderImage || 'node:20-alpine';
+        const runtimeBase = context.runtimeBaseImage || 'node:20-alpine';
+        const user = this.getRuntimeUser(context, 'node');
+        const portDeclaration = this.getPortDeclaration(port);
+        // A build script (bundled frontend, compiled TypeScript) must succeed; otherwise compile TypeScript if present
+        const build = context.buildScript
+            ? `${packageManager} run build`
+            : `if [ -f "tsconfig.json" ]; then ${packageManager} run build || true; fi`;
+        const buildOutputs = context.buildScript ? `
+    (cp -r build /app/prod/ 2>/dev/null || true) && \\
+    (cp -r public /app/prod/ 2>/dev/null || true) && \\` : '';
 
         return `# Multi-stage build for Node.js backend
-FROM node:20-alpine AS builder
+FROM ${this.getBuilderPlatform(context)}${builderImage} AS builder
 
 WORKDIR /app
 
 # Copy package files
 COPY ${packageFiles} ./
 
-# Install ALL dependencies (including dev for build)
-RUN ${packageManager} install
+${pmSetup}# Install ALL dependencies (including dev for build)
+RUN ${this.getSecretMount(context, 'npmrc')}${this.getNodeInstallCommand(packageManager, false, context.lockFile)}
 
 # Copy source
 COPY . .
 
 # Build and prepare production files
-RUN if [ -f "tsconfig.json" ]; then ${packageManager} run build || true; fi && \\
+RUN ${build} && \\
     mkdir -p /app/prod && \\
-    (cp -r dist /app/prod/ 2>/dev/null || true) && \\
+    (cp -r dist /app/prod/ 2>/dev/null || true) && \\${buildOutputs}
     (cp -r src /app/prod/ 2>/dev/null || true) && \\
     (cp *.js /app/prod/ 2>/dev/null || true) && \\
     (cp ${entryPoint} /app/prod/ 2>/dev/null || true)
 
 # Production stage
-FROM node:20-alpine
+FROM ${runtimeBase}
 
 WORKDIR /app
 
@@ -348,20 +597,19 @@ ENV NODE_ENV=production
 # Copy package files
 COPY ${packageFiles} ./
 
-# Install production dependencies only
-RUN ${install}
+${pmSetup}# Install production dependencies only
+RUN ${this.getSecretMount(context, 'npmrc')}${install}
 
 # Copy built files or source
-COPY --from=builder /app/prod ./
+COPY ${user.chown}--from=builder /app/prod ./
 
-# Expose port
-EXPOSE ${port}
+${portDeclaration}
 
 # Health check
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \\
-    CMD wget --quiet --tries=1 --spider http://localhost:${port}/health || exit 1
+    CMD wget --quiet --tries=1 --spider http://localhost:\${PORT}${healthCheckPath} || exit 1
 
-# Start application
+${user.switchUser}# Start application
 CMD ["node", "${entryPoint}"]
 `;
     }
@@ -370,16 +618,37 @@ CMD ["node", "${entryPoint}"]
      * TEMPLATE: Python Backend
      */
     private static getPythonBackendTemplate(context: TemplateContext): string {
-        const { backendFramework = 'fastapi', entryPoint = 'main.py', port = 8000 } = context;
+        const {
+            backendFramework = 'fastapi',
+            entryPoint = 'main.py',
+            port = 8000,
+            languageVersion = '3.11',
+            dependencyFile,
+            asgiApp = 'main:app',
+            healthCheckPath = '/health'
+        } = context;
+
+        if (context.runtimeImage === 'distroless') {
+            return this.getPythonDistrolessTemplate(context);
+        }
+
+        const builderImage = context.builderImage || `python:${languageVersion}-slim`;
+        const runtimeBase = context.runtimeBaseImage || `python:${languageVersion}-slim`;
+
+        // Shell form so the server binds $PORT (set from ARG PORT below)
+        const command = backendFramework.includes('django') ?
+            `"sh", "-c", "exec python manage.py runserver 0.0.0.0:\${PORT}"` :
+            backendFramework.includes('flask') ?
+                `"python", "${entryPoint}"` :
+                `"sh", "-c", "exec uvicorn ${asgiApp} --host 0.0.0.0 --port \${PORT}"`;
 
-        const command = backendFramework === 'django' ?
-            'python manage.py runserver 0.0.0.0:8000' :
-            backendFramework === 'flask' ?
-                'python app.py' :
-                'uvicorn main:app --host 0.0.0.0 --port 8000';
+        const installDependencies = this.getPythonInstallSteps(dependencyFile);
+        const user = this.getRuntimeUser(context, 'appuser', 'useradd --create-home --shell /usr/sbin/nologin appuser');
+        const portDeclaration = this.getPortDeclaration(port);
+        const userHome = context.runAsRoot ? '/root' : '/home/appuser';
 
         return `# Multi-stage build for Python backend
-FROM python:3.11-slim AS builder
+FROM ${builderImage} AS builder
 
 WORKDIR /app
 
@@ -388,35 +657,113 @@ RUN apt-get update && apt-get install -y --no-install-recommends \\
     gcc \\
     && rm -rf /var/lib/apt/lists/*
 
-# Copy requirements
+${installDependencies}
+
+# Production stage
+FROM ${runtimeBase}
+
+WORKDIR /app
+
+${user.create}ENV PYTHONUNBUFFERED=1
+ENV PATH=${userHome}/.local/bin:$PATH
+
+# Copy installed packages from builder
+COPY ${user.chown}--from=builder /root/.local ${userHome}/.local
+
+# Copy application code
+COPY ${user.chown}. .
+
+${portDeclaration}
+
+# Health check (slim images ship without wget/curl)
+HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \\
+    CMD python -c "import urllib.request; urllib.request.urlopen('http://localhost:\${PORT}${healthCheckPath}')" || exit 1
+
+${user.switchUser}# Start application
+CMD [${command}]
+`;
+    }
+
+    /**
+     * Python dependency install steps
+     * RULE: Keep the dependency layer cached separately from source changes
+     */
+    private static getPythonInstallSteps(dependencyFile?: string, target?: string): string {
+        const pipInstall = `pip install ${target ? `--target ${target}` : '--user'} --no-cache-dir`;
+        return dependencyFile === 'pyproject.toml' ? `# Copy project metadata and source
+COPY pyproject.toml ./
+COPY . .
+
+# Install the project and its dependencies
+RUN ${pipInstall} .` : !dependencyFile ? `# No dependency manifest found - install the ASGI stack directly
+RUN ${pipInstall} fastapi "uvicorn[standard]"` : `# Copy requirements
 COPY requirements.txt .
 
 # Install Python dependencies
-RUN pip install --user --no-cache-dir -r requirements.txt
+RUN ${pipInstall} -r requirements.txt`;
+    }
 
-# Production stage
-FROM python:3.11-slim
+    /**
+     * TEMPLATE: Python Backend (distroless runtime)
+     * RULE: Exec form only - there is no shell, so $PORT is read by Python itself;
+     * the builder is pinned to 3.11 to match the interpreter in distroless/python3-debian12, and pip installs
+     * into a fixed --target so the copy does not depend on the builder's python3.X site-packages path
+     */
+    private static getPythonDistrolessTemplate(context: TemplateContext): string {
+        const {
+            backendFramework = 'fastapi',
+            entryPoint = 'main.py',
+            port = 8000,
+            asgiApp = 'main:app',
+            healthCheckPath = '/health'
+        } = context;
+
+        const builderImage = context.builderImage || 'python:3.11-slim';
+        const runtimeBase = context.runtimeBaseImage || this.getDistrolessImage('python3-debian12', context);
+        const user = this.getRuntimeUser(context, 'nonroot');
+        const portDeclaration = this.getPortDeclaration(port);
+
+        // runserver takes its address as one argument, so Django gets the build-time port
+        const command = backendFramework.includes('django') ?
+            `"python3", "manage.py", "runserver", "0.0.0.0:${port}"` :
+            backendFramework.includes('flask') ?
+                `"python3", "${entryPoint}"` :
+                `"python3", "-c", "import os, uvicorn; uvicorn.run('${asgiApp}', host='0.0.0.0', port=int(os.environ['PORT']))"`;
+
+        return `# Multi-stage build for Python backend (distroless runtime)
+FROM ${builderImage} AS builder
+
+WORKDIR /app
+
+# Install system dependencies
+RUN apt-get update && apt-get install -y --no-install-recommends \\
+    gcc \\
+    && rm -rf /var/lib/apt/lists/*
+
+${this.getPythonInstallSteps(context.dependencyFile, '/opt/python-packages')}
+
+# Production stage (distroless: no shell or package manager)
+FROM ${runtimeBase}
 
 WORKDIR /app
 
 ENV PYTHONUNBUFFERED=1
-ENV PATH=/root/.local/bin:$PATH
+ENV PYTHONPATH=/opt/python-packages
 
 # Copy installed packages from builder
-COPY --from=builder /root/.local /root/.local
+COPY --from=builder /opt/python-packages /opt/python-packages
 
 # Copy application code
-COPY . .
+COPY ${user.chown}. .
 
-# Expose port
-EXPOSE ${port}
+${portDeclaration}
 
-# Health check
+# Health check (exec form - Python reads PORT since there is no shell to expand it)
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \\
-    CMD wget --quiet --tries=1 --spider http://localhost:${port}/health || exit 1
+    CMD ["python3", "-c", "import os, urllib.request; urllib.request.urlopen('http://localhost:%s${healthCheckPath}' % os.environ['PORT'])"]
 
-# Start application
-CMD ["${command}"]
+${user.switchUser}# Start application (ENTRYPOINT replaces the image's python3 entrypoint)
+ENTRYPOINT [${command}]
 `;
     }
 
@@ -425,9 +772,14 @@ CMD ["${command}"]
      */
     private static getRubyBackendTemplate(context: TemplateContext): string {
         const { backendFramework = 'rails', port = 3000 } = context;
+        const builderImage = context.builderImage || 'ruby:3.2-alpine';
+        const runtimeBase = context.runtimeBaseImage || 'ruby:3.2-alpine';
+        // Rails writes tmp/ and log/ at runtime, so /app itself must belong to the user
+        const user = this.getRuntimeUser(context, 'appuser', 'addgroup -S appuser && adduser -S -G appuser appuser && chown appuser:appuser /app');
+        const portDeclaration = this.getPortDeclaration(port);
 
         return `# Multi-stage build for Ruby backend
-FROM ruby:3.2-alpine AS builder
+FROM ${builderImage} AS builder
 
 WORKDIR /app
 
@@ -447,11 +799,11 @@ RUN bundle config set --local deployment 'true' && \\
     bundle install
 
 # Production stage
-FROM ruby:3.2-alpine
+FROM ${runtimeBase}
 
 WORKDIR /app
 
-# Install runtime dependencies
+${user.create}# Install runtime dependencies
 RUN apk add --no-cache \\
     postgresql-client \\
     nodejs \\
@@ -461,19 +813,18 @@ RUN apk add --no-cache \\
 COPY --from=builder /usr/local/bundle /usr/local/bundle
 
 # Copy application code
-COPY . .
+COPY ${user.chown}. .
 
-# Precompile assets (Rails)
+${user.switchUser}# Precompile assets (Rails)
 RUN if [ -f "bin/rails" ]; then \\
         RAILS_ENV=production bundle exec rails assets:precompile || true; \\
     fi
 
-# Expose port
-EXPOSE ${port}
+${portDeclaration}
 
 # Health check
 HEALTHCHECK --interval=30s --timeout=3s --start-period=15s --retries=3 \\
-    CMD wget --quiet --tries=1 --spider http://localhost:${port}/ || exit 1
+    CMD wget --quiet --tries=1 --spider http://localhost:\${PORT}/ || exit 1
 
 # Start Rails with Puma
 CMD ["bundle", "exec", "rails", "server", "-b", "0.0.0.0"]
@@ -482,86 +833,298 @@ CMD ["bundle", "exec", "rails", "server", "-b", "0.0.0.0"]
 
     /**
      * TEMPLATE: Java Backend (Spring Boot)
+     * RULE: Build tool follows the build file - pom.xml => mvn package, build.gradle(.kts) => gradle bootJar
      */
     private static getJavaBackendTemplate(context: TemplateContext): string {
-        return `# Multi-stage build for Java backend
-FROM maven:3.9-eclipse-temurin-17 AS builder
+        const { healthCheckPath = '/actuator/health', port = 8080, languageVersion = '17' } = context;
+        const gradle = context.buildTool === 'gradle';
+        const builderImage = context.builderImage || (gradle ? `gradle:8-jdk${languageVersion}` : `maven:3.9-eclipse-temurin-${languageVersion}`);
+        const distroless = context.runtimeImage === 'distroless';
+        const runtimeBase = context.runtimeBaseImage || (distroless
+            ? this.getDistrolessImage(`java${this.getDistrolessJavaVersion(languageVersion)}-debian12`, context)
+            : `eclipse-temurin:${languageVersion}-jre-alpine`);
+        const user = distroless
+            ? this.getRuntimeUser(context, 'nonroot')
+            : this.getRuntimeUser(context, 'appuser', 'addgroup -S appuser && adduser -S -G appuser appuser');
+        const portDeclaration = this.getPortDeclaration(port, [], ['SERVER_PORT']);  // Spring Boot reads SERVER_PORT
+
+        const build = gradle
+            ? `# Copy Gradle build scripts
+COPY ${context.dependencyFile || 'build.gradle'} ./
 
-WORKDIR /app
+# Download dependencies
+RUN gradle dependencies --no-daemon > /dev/null 2>&1 || true
 
-# Copy pom.xml
+# Copy source
+COPY src ./src
+
+# Build application
+RUN gradle bootJar --no-daemon -x test`
+            : `# Copy pom.xml
 COPY pom.xml .
 
 # Download dependencies
-RUN mvn dependency:go-offline
+RUN mvn -B dependency:go-offline
 
 # Copy source
 COPY src ./src
 
 # Build application
-RUN mvn clean package -DskipTests
+RUN mvn -B package -DskipTests`;
+        const jarPath = gradle ? '/app/build/libs/*.jar' : '/app/target/*.jar';
 
-# Production stage
-FROM eclipse-temurin:17-jre-alpine
+        // distroless has no shell, wget or curl, so it cannot run a HEALTHCHECK
+        if (distroless) {
+            return `# Multi-stage build for Java backend (distroless runtime)
+FROM ${this.getBuilderPlatform(context)}${builderImage} AS builder
+
+WORKDIR /app
+
+${build}
+
+# Production stage (distroless: no shell or package manager)
+FROM ${runtimeBase}
 
 WORKDIR /app
 
 # Copy JAR from builder
-COPY --from=builder /app/target/*.jar app.jar
+COPY ${user.chown}--from=builder ${jarPath} app.jar
 
-# Expose port
-EXPOSE 8080
+${portDeclaration}
+
+${user.switchUser}# Start application (ENTRYPOINT replaces the image's java -jar entrypoint)
+ENTRYPOINT ["java", "-jar", "app.jar"]
+`;
+        }
+
+        return `# Multi-stage build for Java backend
+FROM ${this.getBuilderPlatform(context)}${builderImage} AS builder
+
+WORKDIR /app
+
+${build}
+
+# Production stage
+FROM ${runtimeBase}
+
+WORKDIR /app
+
+${user.create}# Copy JAR from builder
+COPY ${user.chown}--from=builder ${jarPath} app.jar
+
+${portDeclaration}
 
 # Health check
 HEALTHCHECK --interval=30s --timeout=3s --start-period=20s --retries=3 \\
-    CMD wget --quiet --tries=1 --spider http://localhost:8080/actuator/health || exit 1
+    CMD wget --quiet --tries=1 --spider http://localhost:\${PORT}${healthCheckPath} || exit 1
 
-# Start application
+${user.switchUser}# Start application
 CMD ["java", "-jar", "app.jar"]
 `;
     }
 
+    /**
+     * distroless image reference: the nonroot tag, or untagged when the image should keep root
+     * RULE: Never :latest - the Debian and runtime versions are in the image name, the tag only picks the variant
+     */
+    private static getDistrolessImage(name: string, context: TemplateContext): string {
+        return `gcr.io/distroless/${name}${context.runAsRoot ? '' : ':nonroot'}`;
+    }
+
+    /**
+     * distroless/java tag for a Java version: 17 or 21 (the releases built on Debian 12)
+     * RULE: Older bytecode runs on the next supported JRE; newer than 21 has no image
+     */
+    static getDistrolessJavaVersion(languageVersion: string): '17' | '21' | undefined {
+        const major = parseInt(languageVersion, 10);
+        if (!major || major > 21) return undefined;
+        return major <= 17 ? '17' : '21';
+    }
+
+    /**
+     * Go module download steps and go build flags
+     * RULE: vendor/modules.txt => copy vendor/ and build with -mod=vendor (offline, no go mod download)
+     */
+    private static getGoDependencySteps(context: TemplateContext): { dependencies: string; source: string; buildFlags: string; buildTarget: string } {
+        const goModFiles = context.lockFile ? `go.mod ${context.lockFile}` : 'go.mod';
+        const tagsFlag = this.getGoTagsFlag(context);
+        const copySource = `# Copy source
+COPY . .`;
+
+        if (context.goWorkspace) {
+            return this.getGoWorkspaceSteps(context);
+        }
+
+        if (context.vendored) {
+            return {
+                dependencies: `# Copy go mod files and vendored modules (offline build)
+COPY ${goModFiles} ./
+COPY vendor ./vendor`,
+                source: copySource,
+                buildFlags: ` -mod=vendor${tagsFlag}`,
+                buildTarget: context.entryPoint || '.'
+            };
+        }
+
+        return {
+            dependencies: `${this.getGoPrivateSetup(context)}# Copy go mod files first so the module cache layer survives source changes
+COPY ${goModFiles} ./
+
+# Download dependencies
+RUN ${this.getSecretMount(context, 'netrc')}go mod download`,
+            source: copySource,
+            buildFlags: tagsFlag,
+            buildTarget: context.entryPoint || '.'
+        };
+    }
+
+    /**
+     * go build -tags flag ('' without tags)
+     */
+    private static getGoTagsFlag(context: TemplateContext): string {
+        return context.buildTags?.length ? ` -tags ${context.buildTags.join(',')}` : '';
+    }
+
+    /**
+     * go.work member: copy the workspace file and every module it uses so replace-free
+     * cross-module imports resolve; the service's main package is built from the workspace root
+     */
+    private static getGoWorkspaceSteps(context: TemplateContext): { dependencies: string; source: string; buildFlags: string; buildTarget: string } {
+        const { modulePath, modules } = context.goWorkspace!;
+        const entryPoint = context.entryPoint || '.';
+        const manifests = modules.map(m => m === '.' ? 'COPY go.mod go.sum* ./' : `COPY ${m}/go.mod ${m}/go.sum* ./${m}/`);
+        // A root module already contains every other module
+        const sources = modules.includes('.') ? ['COPY . .'] : modules.map(m => `COPY ${m}/ ./${m}/`);
+        const entry = entryPoint.replace(/^\.\/?/, '');
+        const target = [modulePath === '.' ? '' : modulePath, entry].filter(Boolean).join('/');
+
+        return {
+            dependencies: `${this.getGoPrivateSetup(context)}# Copy go.work and every workspace module's go.mod first so the module cache layer survives source changes
+COPY go.work go.work.sum* ./
+${manifests.join('\n')}
+
+# Download dependencies for the whole workspace
+RUN ${this.getSecretMount(context, 'netrc')}go mod download`,
+            source: `# Copy workspace module sources
+${sources.join('\n')}`,
+            buildFlags: this.getGoTagsFlag(context),
+            buildTarget: `./${target}`
+        };
+    }
+
     /**
      * TEMPLATE: Go Backend
+     * Two-stage build by default: golang builder + minimal alpine/scratch runtime
      */
     private static getGoBackendTemplate(context: TemplateContext): string {
-        return `# Multi-stage build for Go backend
-FROM golang:1.21-alpine AS builder
+        const { languageVersion = '1.21', port = 8080, runtimeImage = 'alpine', singleStage = false, healthCheckPath = '/health' } = context;
+        const portDeclaration = this.getPortDeclaration(port, (context.ports || []).filter(p => p !== port));
+        const { dependencies, source, buildFlags, buildTarget } = this.getGoDependencySteps(context);
+        const builderImage = context.builderImage || `golang:${languageVersion}-alpine`;
+        const runtimeBase = context.runtimeBaseImage || 'alpine:3.19';
+
+        if (singleStage) {
+            return this.getGoSingleStageTemplate(context);
+        }
+
+        // scratch and distroless have no shell, wget or curl, so they cannot run a HEALTHCHECK
+        // scratch has no /etc/passwd, so it runs as the numeric nobody user; distroless ships nonroot
+        const user = runtimeImage === 'scratch'
+            ? this.getRuntimeUser(context, '65534')
+            : runtimeImage === 'distroless'
+                ? this.getRuntimeUser(context, 'nonroot')
+                : this.getRuntimeUser(context, 'appuser', 'addgroup -S appuser && adduser -S -G appuser appuser');
+        const runtimeStage = runtimeImage === 'distroless' ? `# Production stage (distroless static: CA certificates and /etc/passwd, no shell)
+FROM ${context.runtimeBaseImage || this.getDistrolessImage('static-debian12', context)}
 
 WORKDIR /app
 
-# Copy go mod files
-COPY go.mod go.sum ./
+# Copy binary from builder
+COPY ${user.chown}--from=builder /app/app .
 
-# Download dependencies
-RUN go mod download
+${portDeclaration}` : runtimeImage === 'scratch' ? `# Production stage (static binary only)
+FROM scratch
 
-# Copy source
-COPY . .
+WORKDIR /app
 
-# Build binary
-RUN CGO_ENABLED=0 GOOS=linux go build -o main .
+${context.caCertificates ? `# CA bundle for outbound TLS (scratch has none; golang images ship one)
+COPY --from=builder /etc/ssl/certs/ca-certificates.crt /etc/ssl/certs/
 
-# Production stage
-FROM alpine:latest
+` : ''}# Copy binary from builder
+COPY ${user.chown}--from=builder /app/app .
+
+${portDeclaration}` : `# Production stage
+FROM ${runtimeBase}
 
 WORKDIR /app
 
 # Install ca-certificates
 RUN apk --no-cache add ca-certificates
 
-# Copy binary from builder
-COPY --from=builder /app/main .
+${user.create}# Copy binary from builder
+COPY ${user.chown}--from=builder /app/app .
 
-# Expose port
-EXPOSE 8080
+${portDeclaration}
 
 # Health check
 HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \\
-    CMD wget --quiet --tries=1 --spider http://localhost:8080/health || exit 1
+    CMD wget --quiet --tries=1 --spider http://localhost:\${PORT}${healthCheckPath} || exit 1`;
+
+        // Multi-arch: the builder runs natively and cross-compiles for each target platform
+        const goBuild = context.crossBuild ? `# Cross-compile for the target platform (CGO disabled - no C cross toolchain needed, and the binary runs on ${runtimeImage})
+ARG TARGETOS
+ARG TARGETARCH
+RUN CGO_ENABLED=0 GOOS=\${TARGETOS} GOARCH=\${TARGETARCH} go build${buildFlags} -o app ${buildTarget}` : `# Build static binary (CGO disabled so it runs on ${runtimeImage})
+RUN CGO_ENABLED=0 GOOS=linux go build${buildFlags} -o app ${buildTarget}`;
+
+        return `# Multi-stage build for Go backend
+FROM ${this.getBuilderPlatform(context)}${builderImage} AS builder
+
+WORKDIR /app
+
+${dependencies}
+
+${source}
+
+${goBuild}
 
-# Start application
-CMD ["./main"]
+${runtimeStage}
+
+${user.switchUser}# Start application
+CMD ["./app"]
+`;
+    }
+
+    /**
+     * TEMPLATE: Go Backend (single stage, full toolchain in the final image)
+     */
+    private static getGoSingleStageTemplate(context: TemplateContext): string {
+        const { languageVersion = '1.21', port = 8080, healthCheckPath = '/health' } = context;
+        const portDeclaration = this.getPortDeclaration(port, (context.ports || []).filter(p => p !== port));
+        const { dependencies, source, buildFlags, buildTarget } = this.getGoDependencySteps(context);
+        const builderImage = context.builderImage || `golang:${languageVersion}-alpine`;
+        const user = this.getRuntimeUser(context, 'appuser', 'addgroup -S appuser && adduser -S -G appuser appuser');
+
+        return `# Single-stage build for Go backend
+FROM ${builderImage}
+
+WORKDIR /app
+
+${dependencies}
+
+${source}
+
+# Build binary
+RUN go build${buildFlags} -o app ${buildTarget}
+
+${user.create}${portDeclaration}
+
+# Health check
+HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \\
+    CMD wget --quiet --tries=1 --spider http://localhost:\${PORT}${healthCheckPath} || exit 1
+
+${user.switchUser}# Start application
+CMD ["./app"]
 `;
     }
 
@@ -569,26 +1132,31 @@ CMD ["./main"]
      * TEMPLATE: PHP Backend (Laravel)
      */
     private static getPhpBackendTemplate(context: TemplateContext): string {
+        const builderImage = context.builderImage || 'php:8.2-fpm';
+        const runtimeBase = context.runtimeBaseImage || 'php:8.2-fpm';
+        const user = this.getRuntimeUser(context, 'www-data');
+
         return `# Multi-stage build for PHP backend
-FROM php:8.2-fpm AS builder
+FROM ${builderImage} AS builder
 
 WORKDIR /app
 
 # Install dependencies
-RUN apt-get update && apt-get install -y \\
+RUN apt-get update && apt-get install -y --no-install-recommends \\
     git \\
     curl \\
     libpng-dev \\
     libonig-dev \\
     libxml2-dev \\
     zip \\
-    unzip
+    unzip \\
+    && rm -rf /var/lib/apt/lists/*
 
 # Install PHP extensions
 RUN docker-php-ext-install pdo_mysql mbstring exif pcntl bcmath gd
 
 # Install Composer
-COPY --from=composer:latest /usr/bin/composer /usr/bin/composer
+COPY --from=composer:2 /usr/bin/composer /usr/bin/composer
 
 # Copy composer files
 COPY composer.json composer.lock ./
@@ -600,101 +1168,145 @@ RUN composer install --no-dev --optimize-autoloader
 COPY . .
 
 # Production stage
-FROM php:8.2-fpm
+FROM ${runtimeBase}
 
 WORKDIR /app
 
 # Install runtime dependencies
-RUN apt-get update && apt-get install -y \\
+RUN apt-get update && apt-get install -y --no-install-recommends \\
     libpng-dev \\
-    && docker-php-ext-install pdo_mysql
+    && docker-php-ext-install pdo_mysql \\
+    && rm -rf /var/lib/apt/lists/*
 
 # Copy application from builder
-COPY --from=builder /app ./
+COPY ${user.chown}--from=builder /app ./
 
 # Expose port
 EXPOSE 9000
 
-# Start PHP-FPM
+${user.switchUser}# Start PHP-FPM
 CMD ["php-fpm"]
 `;
     }
 
     /**
      * TEMPLATE: .NET Backend
+     * Project files are restored before the source copy; the publish target and image tags follow the project
      */
     private static getDotnetBackendTemplate(context: TemplateContext): string {
+        const { port = 8080, healthCheckPath = '/health', languageVersion = '8.0' } = context;
+        const projectFile = context.entryPoint || '*.csproj';
+        const assemblyName = context.binaryName || 'app';
+        const builderImage = context.builderImage || `mcr.microsoft.com/dotnet/sdk:${languageVersion}`;
+        const runtimeBase = context.runtimeBaseImage || `mcr.microsoft.com/dotnet/aspnet:${languageVersion}`;
+        // aspnet images ship an unprivileged 'app' user from .NET 8 on; older images need one created
+        const user = parseFloat(languageVersion) >= 8
+            ? this.getRuntimeUser(context, 'app')
+            : this.getRuntimeUser(context, 'app', 'groupadd --system app && useradd --system --gid app --no-create-home app');
+        // ASP.NET Core listens on ASPNETCORE_URLS; tie it to PORT like every other stack
+        const portDeclaration = this.getPortDeclaration(port, [], ['ASPNETCORE_URLS=http://+:${PORT}']);
+
+        // -f picks one framework when the project multi-targets (net5+ TFMs dropped the "coreapp")
+        const targetFramework = context.languageVersion
+            ? ` -f ${parseFloat(languageVersion) >= 5 ? 'net' : 'netcoreapp'}${languageVersion}`
+            : '';
+
+        // Keep each project file at its path so project references resolve during restore
+        const restoreFiles = (context.dependencyFile || projectFile).split(' ');
+        const copyProjects = restoreFiles
+            .map(f => `COPY ${f} ${f.includes('/') ? f.slice(0, f.lastIndexOf('/') + 1) : './'}`)
+            .join('\n');
+
         return `# Multi-stage build for .NET backend
-FROM mcr.microsoft.com/dotnet/sdk:8.0 AS builder
+FROM ${builderImage} AS builder
 
-WORKDIR /app
+WORKDIR /src
 
-# Copy csproj and restore
-COPY *.csproj ./
-RUN dotnet restore
+# Copy project files and restore
+${copyProjects}
+RUN dotnet restore "${projectFile}"
 
-# Copy source and build
-COPY . ./
-RUN dotnet publish -c Release -o out
+# Copy source and publish
+COPY . .
+RUN dotnet publish "${projectFile}" -c Release${targetFramework} -o /app/out --no-restore
 
 # Production stage
-FROM mcr.microsoft.com/dotnet/aspnet:8.0
+FROM ${runtimeBase}
 
 WORKDIR /app
 
-# Copy published app
-COPY --from=builder /app/out .
+# Install curl (health check)
+RUN apt-get update && apt-get install -y --no-install-recommends curl \\
+    && rm -rf /var/lib/apt/lists/*
 
-# Expose port
-EXPOSE 80
+${user.create}# Copy published app
+COPY ${user.chown}--from=builder /app/out .
+
+${portDeclaration}
 
 # Health check
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \\
-    CMD curl --fail http://localhost/health || exit 1
+    CMD curl --fail --silent http://localhost:\${PORT}${healthCheckPath} || exit 1
 
-# Start application
-ENTRYPOINT ["dotnet", "app.dll"]
+${user.switchUser}# Start application
+ENTRYPOINT ["dotnet", "${assemblyName}.dll"]
 `;
     }
 
     /**
      * TEMPLATE: Rust Backend
+     * Dependencies are compiled against a dummy main.rs first so the layer survives source changes
      */
     private static getRustBackendTemplate(context: TemplateContext): string {
+        const { binaryName = 'app', languageVersion, port = 8080, healthCheckPath = '/health' } = context;
+        const cargoFiles = context.lockFile ? `Cargo.toml ${context.lockFile}` : 'Cargo.toml';
+        // Cargo names dependency artifacts after the crate, with '-' replaced by '_'
+        const crateName = binaryName.replace(/-/g, '_');
+        const builderImage = context.builderImage || (languageVersion ? `rust:${languageVersion}-slim` : 'rust:slim');
+        const runtimeBase = context.runtimeBaseImage || 'debian:bookworm-slim';
+        const user = this.getRuntimeUser(context, 'appuser', 'useradd --create-home --shell /usr/sbin/nologin appuser');
+        const portDeclaration = this.getPortDeclaration(port);
+
         return `# Multi-stage build for Rust backend
-FROM rust:1.75-alpine AS builder
+FROM ${builderImage} AS builder
 
 WORKDIR /app
 
-# Install build dependencies
-RUN apk add --no-cache musl-dev
-
 # Copy Cargo files
-COPY Cargo.toml Cargo.lock ./
+COPY ${cargoFiles} ./
+
+# Build dependencies only (dummy main) so this layer is cached
+RUN mkdir src && \\
+    echo "fn main() {}" > src/main.rs && \\
+    cargo build --release && \\
+    rm -rf src target/release/deps/${crateName}*
 
 # Copy source
-COPY src ./src
+COPY . .
 
 # Build release binary
-RUN cargo build --release
+RUN touch src/main.rs && cargo build --release
 
 # Production stage
-FROM alpine:latest
+FROM ${runtimeBase}
 
 WORKDIR /app
 
-# Copy binary from builder
-COPY --from=builder /app/target/release/app ./
+# Install CA certificates and curl (health check)
+RUN apt-get update && apt-get install -y --no-install-recommends ca-certificates curl \\
+    && rm -rf /var/lib/apt/lists/*
 
-# Expose port
-EXPOSE 8080
+${user.create}# Copy binary from builder
+COPY ${user.chown}--from=builder /app/target/release/${binaryName} ./${binaryName}
+
+${portDeclaration}
 
 # Health check
 HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \\
-    CMD wget --quiet --tries=1 --spider http://localhost:8080/health || exit 1
+    CMD curl --fail --silent http://localhost:\${PORT}${healthCheckPath} || exit 1
 
-# Start application
-CMD ["./app"]
+${user.switchUser}# Start application
+CMD ["./${binaryName}"]
 `;
     }
 
@@ -702,8 +1314,14 @@ CMD ["./app"]
      * TEMPLATE: Elixir Backend (Phoenix)
      */
     private static getElixirBackendTemplate(context: TemplateContext): string {
+        const { port = 4000, healthCheckPath = '/health' } = context;
+        const builderImage = context.builderImage || 'elixir:1.15-alpine';
+        const runtimeBase = context.runtimeBaseImage || 'alpine:3.19';
+        const user = this.getRuntimeUser(context, 'appuser', 'addgroup -S appuser && adduser -S -G appuser appuser');
+        const portDeclaration = this.getPortDeclaration(port);
+
         return `# Multi-stage build for Elixir backend
-FROM elixir:1.15-alpine AS builder
+FROM ${builderImage} AS builder
 
 WORKDIR /app
 
@@ -725,25 +1343,181 @@ RUN MIX_ENV=prod mix compile && \\
     MIX_ENV=prod mix release
 
 # Production stage
-FROM alpine:latest
+FROM ${runtimeBase}
 
 WORKDIR /app
 
-# Install runtime dependencies
+${user.create}# Install runtime dependencies
 RUN apk add --no-cache openssl ncurses-libs
 
 # Copy release from builder
-COPY --from=builder /app/_build/prod/rel/app ./
+COPY ${user.chown}--from=builder /app/_build/prod/rel/app ./
 
-# Expose port
-EXPOSE 4000
+${portDeclaration}
 
 # Health check
 HEALTHCHECK --interval=30s --timeout=3s --start-period=10s --retries=3 \\
-    CMD wget --quiet --tries=1 --spider http://localhost:4000/health || exit 1
+    CMD wget --quiet --tries=1 --spider http://localhost:\${PORT}${healthCheckPath} || exit 1
 
-# Start application
+${user.switchUser}# Start application
 CMD ["./bin/app", "start"]
 `;
     }
+
+    /**
+     * TEMPLATE: .env.example listing env vars referenced in source
+     * Variables are grouped by the compose services that read them
+     */
+    static getEnvExampleTemplate(usage: Map<string, string[]>): string {
+        const groups = new Map<string, string[]>();
+        for (const [name, services] of [...usage.entries()].sort(([a], [b]) => a.localeCompare(b))) {
+            const key = services.join(', ');
+            groups.set(key, [...(groups.get(key) || []), name]);
+        }
+
+        let content = `# Environment variables referenced in source code
+# Copy to .env and fill in values - docker-compose.yml passes them through as VAR=\${VAR}
+`;
+        for (const [services, names] of groups) {
+            content += `\n# Used by: ${services}\n`;
+            content += names.map(name => `${name}=`).join('\n') + '\n';
+        }
+        return content;
+    }
+
+    /**
+     * Append variables missing from an existing .env.example (existing lines are kept as-is)
+     */
+    static mergeEnvExample(existing: string, generated: string): string {
+        const defined = new Set(
+            existing.split(/\r?\n/)
+                .map(line => line.match(/^\s*#?\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=/))
+                .filter((m): m is RegExpMatchArray => !!m)
+                .map(m => m[1])
+        );
+
+        const missing = generated.split('\n')
+            .filter(line => /^[A-Za-z_][A-Za-z0-9_]*=/.test(line))
+            .filter(line => !defined.has(line.slice(0, line.indexOf('='))));
+
+        if (missing.length === 0) {
+            return existing;
+        }
+
+        const base = existing.endsWith('\n') ? existing : `${existing}\n`;
+        return `${base}\n# Added by Auto Docker (referenced in source)\n${missing.join('\n')}\n`;
+    }
+
+    /**
+     * TEMPLATE: .dockerignore tuned to the service stack
+     * Written next to each service Dockerfile so its build context stays small
+     */
+    static getDockerignoreTemplate(language: TemplateContext['language'] | 'frontend', options: { excludeVendor?: boolean } = {}): string {
+        const { excludeVendor = true } = options;
+
+        const common = `# Version control
+.git/
+.gitignore
+
+# Environment files
+.env
+.env.*
+
+# Editor & OS files
+.vscode/
+.idea/
+*.swp
+*.swo
+.DS_Store
+Thumbs.db
+
+# Docker files
+Dockerfile*
+docker-compose*.yml
+
+# Logs
+*.log
+`;
+
+        switch (language) {
+            case 'go':
+                return `${common}
+# Go build & test artifacts
+*.test
+*.out
+coverage.txt
+/app
+${excludeVendor ? 'vendor/\n' : ''}`;
+            case 'node':
+            case 'frontend':
+                return `${common}
+# Node dependencies & build outputs
+node_modules/
+dist/
+build/
+.next/
+.nuxt/
+coverage/
+npm-debug.log*
+yarn-debug.log*
+yarn-error.log*
+`;
+            case 'python':
+                return `${common}
+# Python caches & virtualenvs
+__pycache__/
+*.pyc
+.venv/
+venv/
+.pytest_cache/
+`;
+            case 'java':
+            case 'kotlin':
+            case 'scala':
+                return `${common}
+# JVM build outputs
+target/
+build/
+.gradle/
+`;
+            case 'rust':
+                return `${common}
+# Cargo build output
+target/
+`;
+            case 'dotnet':
+                return `${common}
+# .NET build outputs
+bin/
+obj/
+`;
+            default:
+                return common;
+        }
+    }
+
+    /**
+     * Merge .dockerignore content
+     * Existing lines are kept in order; generated lines are appended only if missing
+     */
+    static mergeDockerignore(existing: string, generated: string): string {
+        const seen = new Set(
+            existing.split('\n').map(l => l.trim()).filter(l => l && !l.startsWith('#'))
+        );
+
+        const additions: string[] = [];
+        for (const line of generated.split('\n')) {
+            const entry = line.trim();
+            if (!entry || entry.startsWith('#') || seen.has(entry)) continue;
+            seen.add(entry);
+            additions.push(entry);
+        }
+
+        if (additions.length === 0) {
+            return existing;
+        }
+
+        const base = existing.endsWith('\n') || existing === '' ? existing : `${existing}\n`;
+        return `${base}\n# Added by Auto Docker\n${additions.join('\n')}\n`;
+    }
 }
@@ -0,0 +1,86 @@
+import * as assert from 'assert';
+import { CustomTemplateData, parseTemplate, renderCustomTemplate } from '../templates/custom/customTemplates';
+
+const FILE = 'templates/go.Dockerfile.tmpl';
+const render = (source: string, data: Partial<CustomTemplateData> = {}) =>
+    renderCustomTemplate(parseTemplate(source, FILE), { builtin: 'FROM golang:1.22-alpine\n', stack: 'go', ...data });
+
+describe('custom templates', () => {
+
+    describe('parsing', () => {
+        it('reports an unknown field with file:line', () => {
+            assert.throws(() => parseTemplate('FROM alpine:3.19\n\nEXPOSE {{ .prot }}\n', FILE),
+                /^Error: templates\/go\.Dockerfile\.tmpl:3: unknown field "\.prot" \(available: /);
+        });
+
+        it('does not take object built-ins for fields or functions', () => {
+            assert.throws(() => parseTemplate('{{ .constructor }}', FILE), /:1: unknown field "\.constructor"/);
+            assert.throws(() => parseTemplate('{{ toString .port }}', FILE), /:1: "toString" is not a function/);
+        });
+
+        it('reports an unclosed block at the line it opened', () => {
+            assert.throws(() => parseTemplate('FROM alpine:3.19\n{{ if .port }}\nEXPOSE {{ .port }}\n', FILE),
+                /^Error: templates\/go\.Dockerfile\.tmpl:2: "if" is never closed with "end"$/);
+        });
+
+        it('takes any field on the element inside range and with, and checks $ against the root', () => {
+            assert.doesNotThrow(() => parseTemplate('{{ with .buildSecret }}{{ .id }}{{ end }}', FILE));
+            assert.throws(() => parseTemplate('{{ range .ports }}{{ $.prot }}{{ end }}', FILE), /:1: unknown field "\.prot"/);
+        });
+
+        // range/with's else renders with the outer "." - the root again at the top level
+        it('checks fields in a range else against the root, and after end', () => {
+            assert.throws(() => parseTemplate('{{ range .ports }}{{ .x }}{{ else }}{{ .prot }}{{ end }}', FILE), /:1: unknown field "\.prot"/);
+            assert.throws(() => parseTemplate('{{ with .buildSecret }}{{ .id }}{{ end }}\n{{ .prot }}', FILE), /:2: unknown field "\.prot"/);
+            assert.throws(() => parseTemplate('{{ range .ports }}{{ else }}{{ end }}\n{{ .prot }}', FILE), /:2: unknown field "\.prot"/);
+        });
+
+        it('keeps an outer with scoped inside a nested range else', () => {
+            assert.doesNotThrow(() => parseTemplate('{{ with .buildSecret }}{{ range .hosts }}{{ else }}{{ .reason }}{{ end }}{{ end }}', FILE));
+        });
+    });
+
+    describe('rendering', () => {
+        it('trims whitespace around {{- and -}}', () => {
+            assert.strictEqual(render('EXPOSE  \n  {{- .port -}}  \n!', { port: 8080 }), 'EXPOSE8080!');
+            assert.strictEqual(render('a {{ .port }} b', { port: 8080 }), 'a 8080 b');
+        });
+
+        it('drops comment actions', () => {
+            assert.strictEqual(render('{{/* house style */}}FROM alpine:3.19'), 'FROM alpine:3.19');
+        });
+
+        it('picks the first true branch of if / else if / else', () => {
+            const source = '{{ if eq .packageManager "pnpm" }}pnpm{{ else if .packageManager }}{{ .packageManager }}{{ else }}npm{{ end }}';
+            assert.strictEqual(render(source, { packageManager: 'pnpm' }), 'pnpm');
+            assert.strictEqual(render(source, { packageManager: 'yarn' }), 'yarn');
+            assert.strictEqual(render(source), 'npm');
+        });
+
+        it('reads the root through $ inside range', () => {
+            assert.strictEqual(render('{{ range .ports }}EXPOSE {{ . }}/{{ $.binaryName }}\n{{ end }}', { ports: [80, 443], binaryName: 'app' }),
+                'EXPOSE 80/app\nEXPOSE 443/app\n');
+        });
+
+        it('renders range else and with else against the outer value', () => {
+            assert.strictEqual(render('{{ range .ports }}{{ . }}{{ else }}{{ .port }}{{ end }}', { ports: [], port: 3000 }), '3000');
+            assert.strictEqual(render('{{ with .buildSecret }}{{ .id }}{{ else }}{{ .stack }}{{ end }}'), 'go');
+            assert.strictEqual(render('{{ with .buildSecret }}{{ .id }}{{ end }}', { buildSecret: { id: 'netrc', reason: 'private modules' } }), 'netrc');
+        });
+
+        it('wraps the built-in Dockerfile', () => {
+            assert.strictEqual(render('# team header\n{{ .builtin }}'), '# team header\nFROM golang:1.22-alpine\n');
+        });
+
+        it('applies join and default', () => {
+            assert.strictEqual(render('{{ join .buildTags "," }} {{ default "main" .entryPoint }}', { buildTags: ['prod', 'netgo'] }), 'prod,netgo main');
+        });
+
+        it('reports a type error at render time with file:line', () => {
+            assert.throws(() => render('FROM alpine:3.19\n{{ range .port }}{{ . }}{{ end }}', { port: 8080 }),
+                /^Error: templates\/go\.Dockerfile\.tmpl:2: range over number - only lists can be ranged over$/);
+            assert.throws(() => render('\n\nEXPOSE {{ .ports }}', { ports: [80] }),
+                /^Error: templates\/go\.Dockerfile\.tmpl:3: cannot print a list - use range or join$/);
+        });
+    });
+});
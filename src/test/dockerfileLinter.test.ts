@@ -0,0 +1,190 @@
+import * as assert from 'assert';
+import { Finding, lintDockerfile } from '../dockerfileLinter';
+import { TemplateContext, TemplateManager } from '../templates/templateManager';
+
+const lint = (...lines: string[]) => lintDockerfile(lines.join('\n'));
+const only = (findings: Finding[], rule: string) => findings.filter(f => f.rule === rule);
+
+describe('lintDockerfile', () => {
+
+    describe('DL3007 :latest', () => {
+        it('warns on a :latest base image', () => {
+            const [finding] = only(lint('FROM node:latest', 'CMD ["node"]'), 'DL3007');
+            assert.deepStrictEqual(finding, { rule: 'DL3007', severity: 'warning', line: 1, message: 'node:latest: pin a version instead of :latest' });
+        });
+
+        it('accepts pinned tags and digests', () => {
+            assert.deepStrictEqual(only(lint('FROM node:20-alpine', 'CMD ["node"]'), 'DL3007'), []);
+            assert.deepStrictEqual(only(lint('FROM node@sha256:0000000000000000000000000000000000000000000000000000000000000000', 'CMD ["node"]'), 'DL3007'), []);
+        });
+    });
+
+    describe('DL3006 untagged image', () => {
+        it('warns on an untagged base image', () => {
+            const findings = only(lint('FROM ubuntu', 'CMD ["bash"]'), 'DL3006');
+            assert.strictEqual(findings.length, 1);
+            assert.strictEqual(findings[0].severity, 'warning');
+        });
+
+        it('does not count earlier stages, scratch or build args as images', () => {
+            const findings = lint(
+                'ARG BASE=alpine:3.19',
+                'FROM golang:1.21-alpine AS builder',
+                'FROM builder AS test',
+                'FROM ${BASE}',
+                'FROM scratch',
+                'CMD ["./app"]'
+            );
+            assert.deepStrictEqual(only(findings, 'DL3006'), []);
+        });
+
+        it('accepts untagged distroless images, whose name carries the version', () => {
+            assert.deepStrictEqual(only(lint('FROM gcr.io/distroless/java21-debian12', 'CMD ["app.jar"]'), 'DL3006'), []);
+            assert.strictEqual(only(lint('FROM gcr.io/distroless/java', 'CMD ["app.jar"]'), 'DL3006').length, 1);
+        });
+    });
+
+    describe('DL3022 COPY --from', () => {
+        it('fails on a stage that was never declared', () => {
+            const [finding] = only(lint('FROM golang:1.21-alpine AS build', 'FROM alpine:3.19', 'COPY --from=builder /app/app .', 'CMD ["./app"]'), 'DL3022');
+            assert.strictEqual(finding.severity, 'error');
+            assert.strictEqual(finding.line, 3);
+        });
+
+        it('accepts declared stages, stage indexes and images', () => {
+            const findings = lint(
+                'FROM golang:1.21-alpine AS builder',
+                'FROM alpine:3.19',
+                'WORKDIR /app',
+                'COPY --from=builder /app/app .',
+                'COPY --from=0 /app/app ./copy',
+                'COPY --from=composer:2 /usr/bin/composer /usr/bin/composer',
+                'CMD ["./app"]'
+            );
+            assert.deepStrictEqual(only(findings, 'DL3022'), []);
+        });
+    });
+
+    describe('DL3011 EXPOSE', () => {
+        it('fails on ports out of range and undeclared ARGs', () => {
+            const findings = only(lint('FROM alpine:3.19', 'EXPOSE 70000', 'EXPOSE ${PORT}', 'CMD ["./app"]'), 'DL3011');
+            assert.deepStrictEqual(findings.map(f => [f.line, f.severity]), [[2, 'error'], [3, 'error']]);
+        });
+
+        it('accepts port numbers, protocols and declared ARGs', () => {
+            const findings = lint('FROM alpine:3.19', 'ARG PORT=8080', 'EXPOSE ${PORT} 9090/udp', 'EXPOSE $PORT', 'CMD ["./app"]');
+            assert.deepStrictEqual(only(findings, 'DL3011'), []);
+        });
+    });
+
+    describe('AD001 dependencies after COPY . .', () => {
+        it('warns when the install runs after the source copy', () => {
+            const [finding] = only(lint('FROM node:20-alpine', 'WORKDIR /app', 'COPY . .', 'RUN npm ci', 'CMD ["node", "server.js"]'), 'AD001');
+            assert.strictEqual(finding.severity, 'warning');
+            assert.strictEqual(finding.line, 4);
+        });
+
+        it('accepts the manifest copied first, and a new stage resets the source copy', () => {
+            const findings = lint(
+                'FROM golang:1.21-alpine AS builder',
+                'WORKDIR /app',
+                'COPY go.mod go.sum ./',
+                'RUN go mod download',
+                'COPY . .',
+                'FROM node:20-alpine',
+                'WORKDIR /app',
+                'COPY package*.json ./',
+                'RUN npm ci --omit=dev',
+                'CMD ["node", "server.js"]'
+            );
+            assert.deepStrictEqual(only(findings, 'AD001'), []);
+        });
+    });
+
+    describe('AD002 shell form without a shell', () => {
+        it('fails on shell-form CMD in scratch', () => {
+            const [finding] = only(lint('FROM scratch', 'COPY app /app', 'CMD /app'), 'AD002');
+            assert.deepStrictEqual([finding.severity, finding.line], ['error', 3]);
+        });
+
+        it('fails on shell-form RUN and HEALTHCHECK in distroless, including stages built from it', () => {
+            const findings = lint(
+                'FROM gcr.io/distroless/static-debian12:nonroot AS base',
+                'RUN echo hi',
+                'FROM base',
+                'HEALTHCHECK --interval=30s \\',
+                '    CMD wget -q http://localhost/ || exit 1',
+                'ENTRYPOINT ["/app"]'
+            );
+            assert.deepStrictEqual(only(findings, 'AD002').map(f => f.line), [2, 4]);
+        });
+
+        it('accepts exec form, and :debug images that ship a shell', () => {
+            assert.deepStrictEqual(only(lint('FROM gcr.io/distroless/static-debian12:nonroot', 'CMD ["/app"]'), 'AD002'), []);
+            assert.deepStrictEqual(only(lint('FROM gcr.io/distroless/static-debian12:debug', 'RUN echo hi', 'CMD /app'), 'AD002'), []);
+        });
+    });
+
+    it('reports the first line of a continued instruction and skips comments', () => {
+        const findings = lint('# base', 'FROM node:latest', 'RUN apk add \\', '    # build tools', '    git', 'CMD ["node"]');
+        assert.deepStrictEqual(findings.map(f => [f.rule, f.line]), [['DL3007', 2], ['DL3018', 3]]);
+    });
+
+    it('fails a file without FROM', () => {
+        assert.deepStrictEqual(lint('CMD ["node"]').map(f => [f.rule, f.severity]), [['AD000', 'error']]);
+    });
+});
+
+// Generated Dockerfiles are linted before they are written - the built-in templates must never trip a rule
+describe('built-in templates', () => {
+    const frontends: TemplateContext[] = [
+        {},
+        { runAsRoot: true },
+        { framework: 'html', outputFolder: '.' },
+        { packageManager: 'pnpm' },
+        { packageManager: 'yarn', crossBuild: true },
+        { framework: 'nextjs', variant: 'ssr' },
+        { framework: 'nuxt' },
+        { framework: 'sveltekit' }
+    ];
+    const backends: TemplateContext[] = [
+        { language: 'node' },
+        { language: 'node', packageManager: 'pnpm', buildScript: true },
+        { language: 'python' },
+        { language: 'python', backendFramework: 'django' },
+        { language: 'python', backendFramework: 'flask', dependencyFile: 'pyproject.toml' },
+        { language: 'python', runtimeImage: 'distroless', dependencyFile: 'requirements.txt' },
+        { language: 'java' },
+        { language: 'java', buildTool: 'gradle' },
+        { language: 'java', runtimeImage: 'distroless' },
+        { language: 'go', lockFile: 'go.sum' },
+        { language: 'go', runtimeImage: 'scratch', caCertificates: true },
+        { language: 'go', runtimeImage: 'distroless' },
+        { language: 'go', singleStage: true },
+        { language: 'go', vendored: true, buildTags: ['prod'] },
+        { language: 'go', crossBuild: true },
+        { language: 'go', goWorkspace: { modulePath: 'api', modules: ['api', 'lib'] } },
+        { language: 'go', buildSecret: { id: 'netrc', goPrivate: 'git.corp.example', reason: 'private modules' } },
+        { language: 'ruby' },
+        { language: 'php' },
+        { language: 'dotnet' },
+        { language: 'rust' },
+        { language: 'elixir' }
+    ];
+    // --root keeps the distroless default user, untagged rather than :latest
+    const rootBackends: TemplateContext[] = backends
+        .filter(context => context.runtimeImage === 'distroless')
+        .map(context => ({ ...context, runAsRoot: true }));
+    const describeContext = (context: TemplateContext) => JSON.stringify(context).replace(/"/g, '');
+
+    for (const context of frontends) {
+        it(`frontend ${describeContext(context)} lints clean`, () => {
+            assert.deepStrictEqual(lintDockerfile(TemplateManager.getFrontendTemplate(context)), []);
+        });
+    }
+    for (const context of [...backends, ...rootBackends]) {
+        it(`backend ${describeContext(context)} lints clean`, () => {
+            assert.deepStrictEqual(lintDockerfile(TemplateManager.getBackendTemplate(context)), []);
+        });
+    }
+});
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+)
+
+// Proxies /rates to an upstream HTTPS API.
+func main() {
+	http.HandleFunc("/rates", func(w http.ResponseWriter, r *http.Request) {
+		resp, err := http.Get("https://api.example.com/v1/rates")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		io.Copy(w, resp.Body)
+	})
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"net/http"
+
+	_ "github.com/google/uuid"
+)
+
+func main() {
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"net/http"
+
+	"example.com/lib"
+)
+
+func main() {
+	http.HandleFunc("/", lib.Handler)
+	http.ListenAndServe(":8080", nil)
+}
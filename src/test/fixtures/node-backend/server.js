@@ -0,0 +1,7 @@
+const express = require('express');
+
+const app = express();
+
+app.get('/health', (req, res) => res.sendStatus(200));
+
+app.listen(process.env.PORT || 4000);
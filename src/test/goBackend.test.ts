@@ -0,0 +1,112 @@
+import * as assert from 'assert';
+import { detect, generateResult, toFileMap } from '../index';
+import { fixture, generateFor, lineIndex, removeTempProject, runtimeStage, tempProject } from './helpers';
+
+describe('Go backend', () => {
+
+    describe('go directive', () => {
+        it('builds with the toolchain go.mod declares', async () => {
+            const files = await generateFor(fixture('go-server'));
+            assert.match(files['Dockerfile'], /^FROM golang:1\.21-alpine AS builder$/m);
+        });
+
+        for (const [directive, version] of [['1.21.0', '1.21.0'], ['1.22rc1', '1.22rc1'], ['1.23.1 // toolchain pinned below', '1.23.1']]) {
+            it(`reads go ${directive}`, async () => {
+                const dir = tempProject({
+                    'go.mod': `module example.com/app\n\ngo ${directive}\n`,
+                    'main.go': 'package main\n\nfunc main() {}\n'
+                });
+                try {
+                    const project = await detect(dir);
+                    assert.strictEqual(project.detection.backend?.languageVersion, version);
+                } finally {
+                    removeTempProject(dir);
+                }
+            });
+        }
+    });
+
+    // The module download layer must come before the source copy, or every source change re-downloads
+    describe('dependency layer', () => {
+        const assertBefore = (dockerfile: string, first: RegExp, second: RegExp) => {
+            const [a, b] = [lineIndex(dockerfile, first), lineIndex(dockerfile, second)];
+            assert.ok(a >= 0, `no line matches ${first}`);
+            assert.ok(b >= 0, `no line matches ${second}`);
+            assert.ok(a < b, `${first} must come before ${second}`);
+        };
+
+        it('downloads modules from go.mod and go.sum before COPY . .', async () => {
+            const dockerfile = (await generateFor(fixture('go-server')))['Dockerfile'];
+            assertBefore(dockerfile, /^COPY go\.mod go\.sum \.\/$/, /^RUN .*go mod download/);
+            assertBefore(dockerfile, /^RUN .*go mod download/, /^COPY \. \.$/);
+        });
+
+        it('downloads modules from go.mod alone before COPY . . when there is no go.sum', async () => {
+            const dockerfile = (await generateFor(fixture('go-no-sum')))['Dockerfile'];
+            assertBefore(dockerfile, /^COPY go\.mod \.\/$/, /^RUN .*go mod download/);
+            assertBefore(dockerfile, /^RUN .*go mod download/, /^COPY \. \.$/);
+        });
+
+        it('copies vendor/ before COPY . . and downloads nothing when modules are vendored', async () => {
+            const dockerfile = (await generateFor(fixture('go-vendored')))['Dockerfile'];
+            assert.strictEqual(lineIndex(dockerfile, /go mod download/), -1);
+            assertBefore(dockerfile, /^COPY go\.mod go\.sum \.\/$/, /^COPY \. \.$/);
+            assertBefore(dockerfile, /^COPY vendor \.\/vendor$/, /^COPY \. \.$/);
+            assert.match(dockerfile, /go build -mod=vendor /);
+        });
+
+        it('downloads the whole workspace before copying module sources for a go.work member', async () => {
+            const dockerfile = (await generateFor(fixture('go-workspace')))['api/Dockerfile'];
+            assertBefore(dockerfile, /^COPY go\.work /, /^RUN .*go mod download/);
+            assertBefore(dockerfile, /^COPY lib\/go\.mod /, /^RUN .*go mod download/);
+            assertBefore(dockerfile, /^RUN .*go mod download/, /^COPY api\/ \.\/api\/$/);
+            assertBefore(dockerfile, /^RUN .*go mod download/, /^COPY lib\/ \.\/lib\/$/);
+        });
+    });
+
+    // scratch has no CA bundle - a binary making outbound TLS calls needs the builder's copied in
+    describe('CA certificates on scratch', () => {
+        const caCopy = /^COPY --from=builder \/etc\/ssl\/certs\/ca-certificates\.crt /m;
+
+        it('copies the CA bundle into scratch for an HTTPS client', async () => {
+            const dockerfile = (await generateFor(fixture('go-tls-client'), { goRuntimeImage: 'scratch' }))['Dockerfile'];
+            assert.match(runtimeStage(dockerfile), /^FROM scratch$/m);
+            assert.match(runtimeStage(dockerfile), caCopy);
+        });
+
+        it('copies the CA bundle for crypto/tls', async () => {
+            const dir = tempProject({
+                'go.mod': 'module example.com/app\n\ngo 1.21\n',
+                'main.go': 'package main\n\nimport (\n\t"crypto/tls"\n\t"net/http"\n)\n\nfunc main() {\n\t_ = &tls.Config{}\n\thttp.ListenAndServe(":8080", nil)\n}\n'
+            });
+            try {
+                const dockerfile = (await generateFor(dir, { goRuntimeImage: 'scratch' }))['Dockerfile'];
+                assert.match(runtimeStage(dockerfile), caCopy);
+            } finally {
+                removeTempProject(dir);
+            }
+        });
+
+        it('leaves the CA bundle out for a server that makes no outbound calls', async () => {
+            const dockerfile = (await generateFor(fixture('go-server'), { goRuntimeImage: 'scratch' }))['Dockerfile'];
+            assert.match(runtimeStage(dockerfile), /^FROM scratch$/m);
+            assert.doesNotMatch(dockerfile, caCopy);
+        });
+    });
+
+    // Only a scratch runtime stage lacks a probe - --go-single-stage runs in the golang alpine image, which has wget
+    describe('HEALTHCHECK on scratch', () => {
+        const skipped = /HEALTHCHECK skipped - scratch image/;
+
+        it('warns that the HEALTHCHECK is skipped for a scratch runtime stage', async () => {
+            const result = await generateResult(await detect(fixture('go-server')), { goRuntimeImage: 'scratch' });
+            assert.ok(result.warnings.some(warning => skipped.test(warning)), result.warnings.join('\n'));
+        });
+
+        it('does not warn under goSingleStage, which keeps its HEALTHCHECK', async () => {
+            const result = await generateResult(await detect(fixture('go-server')), { goRuntimeImage: 'scratch', goSingleStage: true });
+            assert.match(toFileMap(result)['Dockerfile'], /^HEALTHCHECK /m);
+            assert.ok(!result.warnings.some(warning => skipped.test(warning)), result.warnings.join('\n'));
+        });
+    });
+});
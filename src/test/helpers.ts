@@ -0,0 +1,56 @@
+/**
+ * Test helpers: fixture projects and the library API run on them
+ *
+ * Fixtures live in src/test/fixtures (tsc only compiles the .ts files, so tests read them from the source tree).
+ * Generation never writes to the project - the files come back in memory.
+ */
+
+import * as fs from 'fs';
+import * as os from 'os';
+import * as path from 'path';
+import { DetectionOptions, GeneratedFiles, Options, detect, generate } from '../index';
+
+export const FIXTURES_DIR = path.resolve(__dirname, '..', '..', 'src', 'test', 'fixtures');
+
+export function fixture(name: string): string {
+    return path.join(FIXTURES_DIR, name);
+}
+
+/**
+ * Detect and generate for a project directory
+ */
+export async function generateFor(dir: string, options: Options = {}, detection: DetectionOptions = {}): Promise<GeneratedFiles> {
+    return generate(await detect(dir, detection), options);
+}
+
+/**
+ * Write files (relative path -> content) into a new temp directory; returns its path
+ */
+export function tempProject(files: Record<string, string> = {}): string {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'auto-docker-test-'));
+    for (const [file, content] of Object.entries(files)) {
+        fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+        fs.writeFileSync(path.join(dir, file), content);
+    }
+    return dir;
+}
+
+export function removeTempProject(dir: string): void {
+    fs.rmSync(dir, { recursive: true, force: true });
+}
+
+/**
+ * Everything from the last FROM on - the stage the container runs
+ */
+export function runtimeStage(dockerfile: string): string {
+    const lines = dockerfile.split('\n');
+    const last = lines.map(l => /^FROM\s/i.test(l)).lastIndexOf(true);
+    return lines.slice(last).join('\n');
+}
+
+/**
+ * Line index of the first line matching pattern (-1 when none does)
+ */
+export function lineIndex(dockerfile: string, pattern: RegExp): number {
+    return dockerfile.split('\n').findIndex(l => pattern.test(l));
+}
@@ -0,0 +1,38 @@
+import * as assert from 'assert';
+import * as path from 'path';
+import { hashServiceInputs } from '../incrementalCache';
+import { detect } from '../index';
+import { removeTempProject, tempProject } from './helpers';
+
+// --incremental runs in CI from whatever directory the checkout lands in - that must not regenerate anything
+describe('Incremental input hashes', () => {
+    const files = {
+        'go.mod': 'module example.com/app\n\ngo 1.22\n',
+        'main.go': 'package main\n\nimport "net/http"\n\nfunc main() {\n\thttp.ListenAndServe(":8080", nil)\n}\n',
+        'templates/go.Dockerfile.tmpl': '# house style\n{{ .builtin }}'
+    };
+    const inputsOf = async (dir: string) => {
+        const backend = (await detect(dir)).detection.backend!;
+        return hashServiceInputs(dir, backend, { templatesDir: path.join(dir, 'templates') });
+    };
+
+    it('hashes the same project the same in two checkouts', async () => {
+        const [first, second] = [tempProject(files), tempProject(files)];
+        try {
+            assert.strictEqual(await inputsOf(first), await inputsOf(second));
+        } finally {
+            removeTempProject(first);
+            removeTempProject(second);
+        }
+    });
+
+    it('changes when a template changes', async () => {
+        const [first, second] = [tempProject(files), tempProject({ ...files, 'templates/go.Dockerfile.tmpl': '# other\n{{ .builtin }}' })];
+        try {
+            assert.notStrictEqual(await inputsOf(first), await inputsOf(second));
+        } finally {
+            removeTempProject(first);
+            removeTempProject(second);
+        }
+    });
+});
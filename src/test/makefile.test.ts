@@ -0,0 +1,20 @@
+import * as assert from 'assert';
+import * as fs from 'fs';
+import * as path from 'path';
+import { fixture, generateFor, removeTempProject, tempProject } from './helpers';
+
+// make docker-build and docker compose build must tag the same images
+describe('Makefile', () => {
+    it('defaults PROJECT to the compose project name of the directory', async () => {
+        const go = fixture('go-server');
+        const dir = tempProject(Object.fromEntries(['go.mod', 'go.sum', 'main.go']
+            .map(file => [`My.App/${file}`, fs.readFileSync(path.join(go, file), 'utf-8')])));
+        try {
+            const makefile = (await generateFor(path.join(dir, 'My.App'), { makefile: true }))['Makefile'];
+            assert.match(makefile, /^PROJECT \?= myapp$/m);
+            assert.match(makefile, /-t \$\(PROJECT\)-backend /);
+        } finally {
+            removeTempProject(dir);
+        }
+    });
+});
@@ -0,0 +1,32 @@
+import * as assert from 'assert';
+import { describeNoStack } from '../enhancedDetectionEngine';
+import { detect, generateResult, toFileMap } from '../index';
+import { fixture, generateFor, removeTempProject, tempProject } from './helpers';
+
+describe('No detectable stack', () => {
+    it('rejects an empty directory with what was looked for', async () => {
+        const dir = tempProject();
+        try {
+            await assert.rejects(generateFor(dir), (error: Error) => {
+                assert.strictEqual(error.message, describeNoStack(dir));
+                assert.match(error.message, /looked for .*go\.mod/);
+                assert.match(error.message, /--stack <name>/);
+                return true;
+            });
+        } finally {
+            removeTempProject(dir);
+        }
+    });
+
+    it('skips an undetectable project root in a recursive scan and generates the others', async () => {
+        const project = await detect(fixture('recursive-one-empty'), { recursive: true });
+        assert.deepStrictEqual(project.detection.monorepo?.unrecognized, ['docs']);
+
+        const result = await generateResult(project);
+        const files = toFileMap(result);
+        assert.ok(files['api/Dockerfile'], 'api gets a Dockerfile');
+        assert.ok(!Object.keys(files).some(f => f.startsWith('docs/')), 'nothing is generated for docs');
+        assert.match(files['docker-compose.yml'], /^ {2}backend:$/m);
+        assert.ok(result.warnings.some(w => /no detectable stack: docs$/.test(w)), 'the skipped directory is reported');
+    });
+});
@@ -0,0 +1,37 @@
+import * as assert from 'assert';
+import { fixture, generateFor, removeTempProject, runtimeStage, tempProject } from './helpers';
+
+// Installs must reproduce the committed lock file, in the builder and in the production stage
+describe('Node backend installs', () => {
+    const builderStage = (dockerfile: string) => dockerfile.slice(0, dockerfile.length - runtimeStage(dockerfile).length);
+
+    it('uses npm ci with package-lock.json', async () => {
+        const dockerfile = (await generateFor(fixture('node-backend')))['Dockerfile'];
+        assert.match(builderStage(dockerfile), /^RUN npm ci$/m);
+        assert.match(runtimeStage(dockerfile), /^RUN npm ci --omit=dev$/m);
+        assert.doesNotMatch(dockerfile, /--production/);
+    });
+
+    const lockFiles: Array<[string, string | undefined, string, string]> = [
+        ['yarn', 'yarn.lock', 'yarn install --frozen-lockfile', 'yarn install --frozen-lockfile --production'],
+        ['pnpm', 'pnpm-lock.yaml', 'pnpm install --frozen-lockfile', 'pnpm install --frozen-lockfile --prod'],
+        ['npm without a lock file', undefined, 'npm install', 'npm install --omit=dev']
+    ];
+
+    for (const [name, lockFile, builderInstall, runtimeInstall] of lockFiles) {
+        it(`uses ${builderInstall} for ${name}`, async () => {
+            const dir = tempProject({
+                'package.json': JSON.stringify({ name: 'api', main: 'server.js', dependencies: { express: '^4.19.2' } }),
+                'server.js': "require('express')().listen(3000);\n",
+                ...(lockFile ? { [lockFile]: '\n' } : {})
+            });
+            try {
+                const dockerfile = (await generateFor(dir))['Dockerfile'];
+                assert.match(builderStage(dockerfile), new RegExp(`^RUN ${builderInstall}$`, 'm'));
+                assert.match(runtimeStage(dockerfile), new RegExp(`^RUN ${runtimeInstall}$`, 'm'));
+            } finally {
+                removeTempProject(dir);
+            }
+        });
+    }
+});
@@ -0,0 +1,33 @@
+import * as assert from 'assert';
+import { TemplateManager } from '../templates/templateManager';
+import { fixture, generateFor, runtimeStage } from './helpers';
+
+// The Dockerfile's ARG PORT default, the ENV the app reads and the compose PORT must agree on the detected port
+describe('PORT wiring', () => {
+    const backends: Array<[string, string, number]> = [
+        ['Go', 'go-server', 9090],
+        ['Node', 'node-backend', 4000],
+        ['Python', 'python-backend', 5001]
+    ];
+
+    for (const [stack, name, port] of backends) {
+        it(`${stack}: ARG PORT defaults to the detected ${port} in the runtime stage and compose passes PORT`, async () => {
+            const files = await generateFor(fixture(name));
+            const runtime = runtimeStage(files['Dockerfile']);
+
+            assert.match(runtime, new RegExp(`^ARG PORT=${port}$`, 'm'));
+            assert.match(runtime, /^ENV PORT=\$\{PORT\}$/m);
+            assert.match(runtime, /^EXPOSE \$\{PORT\}/m);
+            assert.match(files['docker-compose.yml'], new RegExp(`^ +environment:\\n(?: +\\S.*\\n)*? +PORT: "${port}"$`, 'm'));
+        });
+    }
+
+    it('Elixir: EXPOSE and the HEALTHCHECK follow the detected port and health path', () => {
+        const runtime = runtimeStage(TemplateManager.getBackendTemplate({ language: 'elixir', port: 4001, healthCheckPath: '/healthz' }));
+
+        assert.match(runtime, /^ARG PORT=4001$/m);
+        assert.match(runtime, /^EXPOSE \$\{PORT\}$/m);
+        assert.match(runtime, /--spider http:\/\/localhost:\$\{PORT\}\/healthz /);
+        assert.doesNotMatch(runtime, /4000/);
+    });
+});
@@ -0,0 +1,16 @@
+import * as assert from 'assert';
+import { fixture, generateFor, runtimeStage } from './helpers';
+
+// distroless/python3 has no pip - packages are installed in the builder and copied to a path on PYTHONPATH
+describe('Python on distroless', () => {
+    it('installs into a fixed --target and copies that directory', async () => {
+        const dockerfile = (await generateFor(fixture('python-backend'), { distroless: true }))['Dockerfile'];
+        const runtime = runtimeStage(dockerfile);
+
+        assert.match(dockerfile, /^RUN pip install --target \/opt\/python-packages --no-cache-dir -r requirements\.txt$/m);
+        assert.match(runtime, /^FROM gcr\.io\/distroless\/python3-debian12:nonroot$/m);
+        assert.match(runtime, /^COPY --from=builder \/opt\/python-packages \/opt\/python-packages$/m);
+        assert.match(runtime, /^ENV PYTHONPATH=\/opt\/python-packages$/m);
+        assert.doesNotMatch(dockerfile, /site-packages|--user/);
+    });
+});
@@ -0,0 +1,82 @@
+import * as assert from 'assert';
+import * as fs from 'fs';
+import * as path from 'path';
+import { detect, generateResult, toFileMap } from '../index';
+import { fixture, removeTempProject, tempProject } from './helpers';
+
+// A Dockerfile that fails lint takes only its own service down; --strict reports every service, then stops
+describe('Lint failures per service', () => {
+    let dir: string;
+
+    const project = (goTemplate: string, extra: Record<string, string> = {}) => tempProject({
+        'api/go.mod': 'module example.com/api\n\ngo 1.22\n',
+        'api/main.go': 'package main\n\nimport "net/http"\n\nfunc main() {\n\thttp.ListenAndServe(":8080", nil)\n}\n',
+        'worker/package.json': JSON.stringify({ name: 'worker', main: 'server.js', dependencies: { express: '^4.19.2' } }),
+        'worker/server.js': "require('express')().listen(4000);\n",
+        'templates/go.Dockerfile.tmpl': goTemplate,
+        ...extra
+    });
+
+    afterEach(() => removeTempProject(dir));
+
+    it('drops a service whose Dockerfile has a lint error and generates the others', async () => {
+        dir = project('FROM alpine:3.19\nWORKDIR /app\nCOPY --from=build /app/app .\nCMD ["./app"]\n');
+        const result = await generateResult(await detect(dir, { recursive: true }), { templatesDir: 'templates' });
+        const files = toFileMap(result);
+
+        assert.ok(!files['api/Dockerfile'], 'the failing Dockerfile is not written');
+        assert.ok(files['worker/Dockerfile'], 'the other service is generated');
+        assert.doesNotMatch(files['docker-compose.yml'], /context: \.\/api/);
+        assert.deepStrictEqual(result.errors.map(e => e.path), ['api']);
+        assert.match(result.errors[0].message, /api\/Dockerfile:3 DL3022 /);
+    });
+
+    // Names come from the detected services - the surviving backend keeps backend_2, and the frontend is wired to it
+    it('keeps the other services\' names and points the frontend at a backend that was generated', async () => {
+        const web = fixture('static-frontend');
+        dir = project('FROM alpine:3.19\nWORKDIR /app\nCOPY --from=build /app/app .\nCMD ["./app"]\n', Object.fromEntries(
+            ['package.json', 'index.html', 'vite.config.js', 'src/main.jsx'].map(file => [`web/${file}`, fs.readFileSync(path.join(web, file), 'utf-8')])));
+        const result = await generateResult(await detect(dir, { recursive: true }), { templatesDir: 'templates' });
+        const compose = toFileMap(result)['docker-compose.yml'];
+
+        assert.deepStrictEqual(result.errors.map(e => e.path), ['api']);
+        assert.match(compose, /^ {2}backend_2:$/m);
+        assert.doesNotMatch(compose, /^ {2}backend(_1)?:$/m);
+        assert.match(compose, /^ +VITE_API_URL: http:\/\/localhost:4000$/m);
+        assert.ok(result.assumptions.includes('frontend reaches the API at http://backend_2:4000'));
+    });
+
+    it('names the surviving service\'s Kubernetes Deployment, Service and image as compose does', async () => {
+        dir = project('FROM alpine:3.19\nWORKDIR /app\nCOPY --from=build /app/app .\nCMD ["./app"]\n');
+        const files = toFileMap(await generateResult(await detect(dir, { recursive: true }), { templatesDir: 'templates', kubernetes: true, projectName: 'shop' }));
+        const manifest = files['k8s/backend-2.yaml'];
+
+        assert.deepStrictEqual(Object.keys(files).filter(f => f.startsWith('k8s/')), ['k8s/backend-2.yaml']);
+        assert.match(manifest, /^kind: Deployment\nmetadata:\n {2}name: backend-2$/m);
+        assert.match(manifest, /^kind: Service\nmetadata:\n {2}name: backend-2$/m);
+        assert.match(manifest, /^ +image: shop-backend_2:latest$/m);
+    });
+
+    it('keeps lint warnings as warnings without --strict', async () => {
+        dir = project('FROM alpine:latest\nWORKDIR /app\nCMD ["./app"]\n');
+        const result = await generateResult(await detect(dir, { recursive: true }), { templatesDir: 'templates' });
+
+        assert.ok(toFileMap(result)['api/Dockerfile']);
+        assert.deepStrictEqual(result.errors, []);
+        assert.ok(result.warnings.some(w => w.startsWith('api/Dockerfile:1 DL3007 ')));
+    });
+
+    it('stops the run under --strict after listing every service\'s findings', async () => {
+        dir = project('FROM alpine:latest\nWORKDIR /app\nCOPY --from=build /app/app .\nCMD ["./app"]\n', {
+            'templates/node.Dockerfile.tmpl': 'FROM node:latest\nWORKDIR /app\nCMD ["node", "server.js"]\n'
+        });
+        const detected = await detect(dir, { recursive: true });
+
+        await assert.rejects(generateResult(detected, { templatesDir: 'templates', strict: true }), (error: Error) => {
+            assert.match(error.message, /^Dockerfile lint failed \(--strict\): /);
+            assert.match(error.message, /api\/Dockerfile:1 DL3007 .*; api\/Dockerfile:3 DL3022 /);
+            assert.match(error.message, /worker\/Dockerfile:1 DL3007 /);
+            return true;
+        });
+    });
+});
@@ -0,0 +1,38 @@
+import * as assert from 'assert';
+import * as fs from 'fs';
+import * as path from 'path';
+import { detect, generateResult, toFileMap } from '../index';
+import { fixture, removeTempProject, tempProject } from './helpers';
+
+// Every generated file refers to a service by its compose name - they must all agree
+describe('Service names', () => {
+    let dir: string;
+
+    beforeEach(() => {
+        const web = fixture('static-frontend');
+        dir = tempProject({
+            ...Object.fromEntries(['package.json', 'index.html', 'vite.config.js', 'src/main.jsx']
+                .map(file => [`web/${file}`, fs.readFileSync(path.join(web, file), 'utf-8')])),
+            'api/go.mod': 'module example.com/api\n\ngo 1.22\n',
+            'api/main.go': 'package main\n\nimport "net/http"\n\nfunc main() {\n\thttp.ListenAndServe(":8080", nil)\n}\n',
+            'worker/package.json': JSON.stringify({ name: 'worker', main: 'server.js', dependencies: { express: '^4.19.2' } }),
+            'worker/server.js': "require('express')().listen(4000);\n"
+        });
+    });
+
+    afterEach(() => removeTempProject(dir));
+
+    it('numbers services only when there are several of a kind, the same way in every file', async () => {
+        const result = await generateResult(await detect(dir, { recursive: true }), { githubWorkflow: {}, kubernetes: true });
+        const files = toFileMap(result);
+        const names = ['frontend', 'backend_1', 'backend_2'];
+
+        for (const name of names) {
+            assert.match(files['docker-compose.yml'], new RegExp(`^ {2}${name}:$`, 'm'));
+            assert.match(files['.github/workflows/docker.yml'], new RegExp(`^ {2}${name}:$`, 'm'));
+            assert.ok(files[`k8s/${name.replace('_', '-')}.yaml`], `k8s/${name.replace('_', '-')}.yaml`);
+        }
+        assert.match(files['docker-compose.yml'], /^ {2}nginx:\n(?: +\S.*\n)*? +depends_on:\n +- frontend$/m);
+        assert.ok(result.assumptions.includes('frontend reaches the API at http://backend_1:8080'));
+    });
+});
@@ -0,0 +1,43 @@
+import * as assert from 'assert';
+import { fixture, generateFor, removeTempProject, runtimeStage, tempProject } from './helpers';
+
+// nginx only needs root to bind port 80 - the unprivileged image listens on 8080 instead
+describe('Static frontend', () => {
+    it('serves the build from unprivileged nginx on 8080', async () => {
+        const files = await generateFor(fixture('static-frontend'));
+        const runtime = runtimeStage(files['Dockerfile']);
+
+        assert.match(runtime, /^FROM nginxinc\/nginx-unprivileged:alpine$/m);
+        assert.match(runtime, /^EXPOSE 8080$/m);
+        assert.match(runtime, /--spider http:\/\/localhost:8080\/ /);
+        assert.match(files['nginx.conf'], /^ {4}listen 8080;$/m);
+        assert.match(files['docker-compose.yml'], /^ +- "3000:8080"$/m);
+    });
+
+    it('keeps nginx:alpine on port 80 under --root', async () => {
+        const files = await generateFor(fixture('static-frontend'), { runAsRoot: true });
+        const runtime = runtimeStage(files['Dockerfile']);
+
+        assert.match(runtime, /^FROM nginx:alpine$/m);
+        assert.match(runtime, /^EXPOSE 80$/m);
+        assert.match(files['nginx.conf'], /^ {4}listen 80;$/m);
+        assert.match(files['docker-compose.yml'], /^ +- "3000:80"$/m);
+    });
+
+    // --stack frontend on an empty directory, or a plain HTML site: there is no package.json to install from
+    it('copies and serves the files without a build stage when there is no package.json', async () => {
+        const dir = tempProject();
+        try {
+            const files = await generateFor(dir, {}, { stack: 'frontend' });
+            const dockerfile = files['Dockerfile'];
+
+            assert.doesNotMatch(dockerfile, /npm|package\*\.json/);
+            assert.strictEqual(dockerfile.match(/^FROM /gm)?.length, 1);
+            assert.match(dockerfile, /^FROM nginxinc\/nginx-unprivileged:alpine$/m);
+            assert.match(dockerfile, /^COPY \. \/usr\/share\/nginx\/html$/m);
+            assert.match(files['nginx.conf'], /^ {4}listen 8080;$/m);
+        } finally {
+            removeTempProject(dir);
+        }
+    });
+});
@@ -0,0 +1,110 @@
+/**
+ * Verify: smoke-test the generated Dockerfiles
+ *
+ * Generates into a temporary copy of the project and, with the docker option, runs
+ * `docker build` for every generated Dockerfile.
+ * RULE: The project itself is never written to - everything happens in the temp copy.
+ */
+
+import * as fs from 'fs';
+import * as os from 'os';
+import * as path from 'path';
+import { spawnSync } from 'child_process';
+import { DockerGenerationOrchestrator, GenerationResult } from './dockerGenerationOrchestrator';
+import { GenerationOptions } from './deterministicDockerGenerator';
+import type { Project } from './index';
+
+/**
+ * Never copied into the temp build context (large, and excluded by the generated .dockerignore anyway)
+ */
+const COPY_EXCLUDED = ['.git', 'node_modules'];
+
+export interface VerifyOptions {
+    docker: boolean;                // Run docker build; otherwise stop after generation + lint
+    generation?: GenerationOptions;
+    log?: (line: string) => void;
+}
+
+export interface VerifyBuild {
+    dockerfile: string;  // Path relative to the project root
+    context: string;     // Build context relative to the project root
+    status: 'passed' | 'failed' | 'skipped';
+    output?: string;     // Tail of the docker build output when the build failed
+}
+
+export interface VerifyResult {
+    generation: GenerationResult;
+    builds: VerifyBuild[];
+    passed: boolean;
+}
+
+/**
+ * Generate the project's Docker files into a temp copy and build each Dockerfile
+ */
+export async function verify(project: Project, options: VerifyOptions): Promise<VerifyResult> {
+    const log = options.log || (() => undefined);
+    // The point is to test what the generator produces, so existing Dockerfiles are replaced in the copy
+    const generation = await new DockerGenerationOrchestrator(project.root, undefined, {
+        ...options.generation,
+        existingDockerfile: 'overwrite'
+    }).generate(project.detection);
+    const outputs = DockerGenerationOrchestrator.getOutputFiles(generation.files);
+    const dockerfiles = outputs
+        .map(f => f.path)
+        .filter(p => /^Dockerfile(\.[\w-]+)?$/.test(path.posix.basename(p)))
+        .sort();
+
+    const tempRoot = fs.mkdtempSync(path.join(os.tmpdir(), 'auto-docker-verify-'));
+    try {
+        fs.cpSync(project.root, tempRoot, {
+            recursive: true,
+            filter: src => !COPY_EXCLUDED.includes(path.basename(src))
+        });
+        DockerGenerationOrchestrator.writeOutputFiles(tempRoot, outputs);
+        log(`[Verify] Generated ${outputs.length} file(s) into ${tempRoot}`);
+
+        const buildContexts = generation.deterministicResult?.buildContexts || {};
+        const builds = dockerfiles.map(dockerfile => {
+            const context = buildContexts[dockerfile] || path.posix.dirname(dockerfile);
+            if (!options.docker) {
+                return { dockerfile, context, status: 'skipped' as const };
+            }
+            log(`[Verify] docker build -f ${dockerfile} ${context}`);
+            return { dockerfile, context, ...dockerBuild(tempRoot, dockerfile, context) };
+        });
+
+        return {
+            generation,
+            builds,
+            passed: generation.errors.length === 0 && builds.every(b => b.status !== 'failed')
+        };
+    } finally {
+        fs.rmSync(tempRoot, { recursive: true, force: true });
+    }
+}
+
+/**
+ * Run one docker build; the image is removed again after a successful build
+ */
+function dockerBuild(root: string, dockerfile: string, context: string): Pick<VerifyBuild, 'status' | 'output'> {
+    const build = spawnSync('docker', ['build', '--quiet', '-f', dockerfile, context], {
+        cwd: root,
+        encoding: 'utf-8',
+        maxBuffer: 64 * 1024 * 1024
+    });
+
+    if (build.error) {
+        const reason = (build.error as NodeJS.ErrnoException).code === 'ENOENT' ? 'docker not found on PATH' : build.error.message;
+        return { status: 'failed', output: reason };
+    }
+    if (build.status !== 0) {
+        const output = `${build.stdout}\n${build.stderr}`.trim().split('\n').slice(-20).join('\n');
+        return { status: 'failed', output };
+    }
+
+    const imageId = build.stdout.trim().split('\n').pop();
+    if (imageId) {
+        spawnSync('docker', ['image', 'rm', '--force', imageId], { encoding: 'utf-8' });
+    }
+    return { status: 'passed' };
+}